@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"tarantool-app/pkg/client"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// newFlagSet returns the flags of a command, the shared ones included.
+func newFlagSet(name, args string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kvctl %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	opts.register(fs)
+	return fs
+}
+
+// parse parses the flags of a command and checks the number of arguments.
+// Flags and arguments may be interleaved.
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) < minArgs || len(positional) > maxArgs {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+func getCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("get", "<key>", opts)
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := opts.client(false)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printEntry(os.Stdout, opts.output, e)
+}
+
+func createCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("create", "<key> [file]", opts)
+	inline := fs.String("value", "", "value as a JSON object instead of a file")
+	args, err := parse(fs, args, 1, 2)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(*inline, fileArg(args), os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	c, err := opts.client(false)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.Create(ctx, args[0], value)
+	if err != nil {
+		return err
+	}
+	return printEntry(os.Stdout, opts.output, e)
+}
+
+func putCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("put", "<key> [file]", opts)
+	inline := fs.String("value", "", "value as a JSON object instead of a file")
+	upsert := fs.Bool("create", false, "create the key when it does not exist")
+	args, err := parse(fs, args, 1, 2)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(*inline, fileArg(args), os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	c, err := opts.client(false)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.Update(ctx, args[0], value)
+	if *upsert && errors.Is(err, client.ErrNotFound) {
+		e, err = c.Create(ctx, args[0], value)
+	}
+	if err != nil {
+		return err
+	}
+	return printEntry(os.Stdout, opts.output, e)
+}
+
+func deleteCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("delete", "<key>", opts)
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := opts.client(false)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.Delete(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printEntry(os.Stdout, opts.output, e)
+}
+
+// listCmd reads the export, the API having no other way to list keys by
+// prefix.
+func listCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("list", "", opts)
+	prefix := fs.String("prefix", "", "list keys starting with the prefix")
+	keysOnly := fs.Bool("keys-only", false, "print one key per line")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	c, err := opts.client(true)
+	if err != nil {
+		return err
+	}
+
+	entries, err := c.List(ctx, *prefix)
+	if err != nil {
+		return err
+	}
+
+	if *keysOnly {
+		for _, e := range entries {
+			fmt.Println(e.Key)
+		}
+		return nil
+	}
+	return printEntries(os.Stdout, opts.output, entries)
+}
+
+// watchCmd prints the value whenever it changes until interrupted.
+func watchCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("watch", "<key>", opts)
+	interval := fs.Duration("interval", 2*time.Second, "polling interval")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	c, err := opts.client(false)
+	if err != nil {
+		return err
+	}
+
+	for event := range c.Watch(ctx, args[0], *interval) {
+		switch event.Type {
+		case client.EventPut:
+			if err := printEntry(os.Stdout, opts.output, event.Entry); err != nil {
+				return err
+			}
+		case client.EventDelete:
+			fmt.Fprintf(os.Stderr, "%s %s deleted\n", time.Now().Format(time.RFC3339), args[0])
+		case client.EventError:
+			return event.Err
+		}
+	}
+	return nil
+}
+
+func exportCmd(ctx context.Context, opts *options, args []string) error {
+	fs := newFlagSet("export", "", opts)
+	prefix := fs.String("prefix", "", "export keys starting with the prefix")
+	format := fs.String("format", "ndjson", "ndjson, csv or msgpack")
+	output := fs.String("file", "-", "file to write, - for stdout")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	c, err := opts.client(true)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.Export(ctx, *prefix, *format)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	_, err = io.Copy(out, body)
+	return err
+}
+
+// fileArg returns the optional file argument following the key.
+func fileArg(args []string) string {
+	if len(args) > 1 {
+		return args[1]
+	}
+	return ""
+}
+
+// readValue reads a value object from inline JSON, from the file, or from
+// stdin when the file is empty or "-".
+func readValue(inline, file string, stdin io.Reader) (map[string]any, error) {
+	var data []byte
+	var err error
+	switch {
+	case inline != "" && file != "":
+		return nil, errors.New("either --value or a file may be given")
+	case inline != "":
+		data = []byte(inline)
+	case file == "" || file == "-":
+		data, err = io.ReadAll(stdin)
+	default:
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var value map[string]any
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &value)
+	default:
+		err = json.Unmarshal(data, &value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("value must be an object: %w", err)
+	}
+	if len(value) == 0 {
+		return nil, errors.New("value must be a non-empty object")
+	}
+	return value, nil
+}
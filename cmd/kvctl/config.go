@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultURL     = "http://localhost:8080"
+	defaultTimeout = 30 * time.Second
+)
+
+// fileConfig is the kvctl config file, holding named profiles:
+//
+//	current: prod
+//	profiles:
+//	  prod:
+//	    url: https://kv.example.com
+//	    token_file: ~/.secrets/kv-admin
+type fileConfig struct {
+	Current  string             `yaml:"current"`
+	Profiles map[string]profile `yaml:"profiles"`
+}
+
+// profile is a server to talk to. The token is sent as a bearer token and
+// is only required by the admin API.
+type profile struct {
+	URL       string        `yaml:"url"`
+	Token     string        `yaml:"token"`
+	TokenFile string        `yaml:"token_file"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// defaultConfigPath returns $KVCTL_CONFIG, or kvctl/config.yaml in the user
+// config directory.
+func defaultConfigPath() string {
+	if path := os.Getenv("KVCTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kvctl", "config.yaml")
+}
+
+// loadProfile reads the named profile, the current one when name is empty.
+// A missing config file is only an error when a profile was asked for.
+func loadProfile(path, name string) (profile, error) {
+	var cfg fileConfig
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if name != "" {
+				return profile{}, fmt.Errorf("profile %q: config file %s does not exist", name, path)
+			}
+		case err != nil:
+			return profile{}, err
+		default:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return profile{}, fmt.Errorf("config file %s: %w", path, err)
+			}
+		}
+	}
+
+	if name == "" {
+		name = cfg.Current
+	}
+	if name == "" {
+		return profile{}, nil
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("profile %q is not defined in %s", name, path)
+	}
+	return p, nil
+}
+
+// resolve applies flags and environment variables over the profile:
+// flags win over KVCTL_URL and KVCTL_TOKEN, which win over the profile.
+func (p profile) resolve(url, token string, timeout time.Duration) (profile, error) {
+	p.URL = firstNonEmpty(url, os.Getenv("KVCTL_URL"), p.URL, defaultURL)
+
+	switch {
+	case token != "":
+		p.Token = token
+	case os.Getenv("KVCTL_TOKEN") != "":
+		p.Token = os.Getenv("KVCTL_TOKEN")
+	case p.Token == "" && p.TokenFile != "":
+		data, err := os.ReadFile(expandHome(p.TokenFile))
+		if err != nil {
+			return profile{}, fmt.Errorf("token file: %w", err)
+		}
+		p.Token = strings.TrimSpace(string(data))
+	}
+
+	if timeout > 0 {
+		p.Timeout = timeout
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = defaultTimeout
+	}
+	return p, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
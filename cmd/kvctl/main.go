@@ -0,0 +1,178 @@
+// Command kvctl is a command-line client of the tarantool-app REST API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"tarantool-app/pkg/client"
+	"time"
+)
+
+const usage = `Usage: kvctl [flags] <command> [arguments]
+
+Commands:
+  get <key>                  print a value
+  create <key> [file]        create a key, failing if it exists
+  put <key> [file]           replace the value of an existing key
+  delete <key>               delete a key and print its last value
+  list [--prefix p]          list keys and values (admin token required)
+  watch <key>                print the value every time it changes
+  export [--prefix p]        stream values as ndjson, csv or msgpack (admin token required)
+
+Values are read from --value, from the file, or from stdin when the file
+is omitted or "-". Files ending in .yaml or .yml are read as YAML.
+
+Flags, accepted before or after the command:
+`
+
+// Exit codes, mapped from the API responses.
+const (
+	exitOK           = 0
+	exitError        = 1 // unexpected responses and local errors
+	exitUsage        = 2
+	exitNotFound     = 3 // 404
+	exitConflict     = 4 // 409
+	exitInvalid      = 5 // 400, 415 and 422
+	exitUnauthorized = 6 // 401 and 403
+	exitServer       = 7 // 5xx
+	exitUnavailable  = 8 // the server could not be reached
+)
+
+// errUsage reports invalid arguments already explained to the user.
+var errUsage = errors.New("invalid arguments")
+
+// options are the flags shared by every command.
+type options struct {
+	config  string
+	profile string
+	url     string
+	token   string
+	output  string
+	timeout time.Duration
+}
+
+// register adds the shared flags to fs, defaulting to the values parsed so
+// far so that flags given before the command are kept.
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", o.config, "config file with profiles")
+	fs.StringVar(&o.profile, "profile", o.profile, "profile of the config file, $KVCTL_PROFILE or the current one by default")
+	fs.StringVar(&o.url, "url", o.url, "API base URL, overrides $KVCTL_URL and the profile")
+	fs.StringVar(&o.token, "token", o.token, "bearer token, overrides $KVCTL_TOKEN and the profile")
+	fs.StringVar(&o.output, "output", o.output, "output format: json, yaml or table")
+	fs.StringVar(&o.output, "o", o.output, "shorthand for --output")
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "timeout of a single request (default 30s)")
+}
+
+// client builds the API client from the flags, environment and profile.
+// Requests time out unless streaming is set.
+func (o *options) client(streaming bool) (*client.Client, error) {
+	if !validOutput(o.output) {
+		return nil, fmt.Errorf("unknown output format %q", o.output)
+	}
+
+	p, err := loadProfile(o.config, o.profile)
+	if err != nil {
+		return nil, err
+	}
+	p, err = p.resolve(o.url, o.token, o.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := &http.Client{Timeout: p.Timeout}
+	if streaming {
+		hc.Timeout = 0
+	}
+	return client.New(p.URL, client.WithToken(p.Token), client.WithHTTPClient(hc))
+}
+
+// command runs a subcommand with its arguments.
+type command func(ctx context.Context, opts *options, args []string) error
+
+var commands = map[string]command{
+	"get":    getCmd,
+	"create": createCmd,
+	"put":    putCmd,
+	"delete": deleteCmd,
+	"list":   listCmd,
+	"watch":  watchCmd,
+	"export": exportCmd,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	opts := &options{
+		config:  defaultConfigPath(),
+		profile: os.Getenv("KVCTL_PROFILE"),
+		output:  outputJSON,
+	}
+
+	global := flag.NewFlagSet("kvctl", flag.ContinueOnError)
+	global.Usage = func() {
+		fmt.Fprint(global.Output(), usage)
+		global.PrintDefaults()
+	}
+	opts.register(global)
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if global.NArg() == 0 {
+		global.Usage()
+		return exitUsage
+	}
+
+	name := global.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		global.Usage()
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := cmd(ctx, opts, global.Args()[1:])
+	if err != nil && !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "kvctl:", err)
+	}
+	return exitCode(err)
+}
+
+// exitCode maps an error, API errors by their status, to the exit code.
+func exitCode(err error) int {
+	var urlErr *url.Error
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, client.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, client.ErrConflict):
+		return exitConflict
+	case errors.Is(err, client.ErrInvalid):
+		return exitInvalid
+	case errors.Is(err, client.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, client.ErrServer):
+		return exitServer
+	case errors.As(err, &urlErr):
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"tarantool-app/pkg/client"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of --output.
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+func validOutput(format string) bool {
+	switch format {
+	case outputJSON, outputYAML, outputTable:
+		return true
+	default:
+		return false
+	}
+}
+
+// printEntry writes an entry as an object, or as a table row.
+func printEntry(out io.Writer, format string, e client.Entry) error {
+	return write(out, format, e, []client.Entry{e})
+}
+
+// printEntries writes entries as a list, or as table rows.
+func printEntries(out io.Writer, format string, entries []client.Entry) error {
+	if entries == nil {
+		entries = []client.Entry{}
+	}
+	return write(out, format, entries, entries)
+}
+
+// write encodes obj in JSON or YAML, or prints rows as a table.
+func write(out io.Writer, format string, obj any, rows []client.Entry) error {
+	switch format {
+	case outputYAML:
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+		return enc.Close()
+	case outputTable:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE")
+		for _, e := range rows {
+			value, err := json.Marshal(e.Value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\n", e.Key, value)
+		}
+		return w.Flush()
+	default:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(obj)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/app"
+	"tarantool-app/internal/domain"
+)
+
+const usage = `Usage: tarantool-app [--config <path>] [--set <path=value>]... <command> [flags]
+
+The config file is taken from --config, then CONFIG_PATH, then %q.
+Settings are layered: defaults, the file, environment variables, then --set,
+e.g. --set sql.max_rows=100.
+
+Commands:
+  serve          run the HTTP server (default)
+  check-config   print the effective config with secrets redacted and validate it
+  migrate        status | up [version] | down <version>
+  export         write values to a file or stdout
+  import         read values from a file or stdin
+  ping           connect and report the server and schema versions
+
+Run "tarantool-app <command> --help" for the flags of a command.
+`
+
+// errUsage reports invalid arguments already explained to the user.
+var errUsage = errors.New("invalid arguments")
+
+// overrides collects repeated --set flags.
+type overrides []string
+
+func (o *overrides) String() string { return strings.Join(*o, ",") }
+
+func (o *overrides) Set(value string) error {
+	*o = append(*o, value)
+	return nil
+}
+
+// sourceFlags are the flags selecting the config, accepted before and
+// after the command.
+type sourceFlags struct {
+	config string
+	set    overrides
+}
+
+func (s *sourceFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.config, "config", s.config, "path to the config file")
+	fs.Var(&s.set, "set", "override a setting as path=value, repeatable")
+}
+
+func (s *sourceFlags) source() config.Source {
+	return config.Source{Path: config.Path(s.config), Overrides: s.set}
+}
+
+// command runs a subcommand with its remaining arguments.
+type command func(flags *sourceFlags, args []string) error
+
+var commands = map[string]command{
+	"serve":        serve,
+	"check-config": checkConfig,
+	"migrate":      migrate,
+	"export":       export,
+	"import":       importValues,
+	"ping":         ping,
+}
+
+// run parses global flags, picks the command and returns the exit code.
+func run(args []string) int {
+	flags := &sourceFlags{}
+
+	global := flag.NewFlagSet("tarantool-app", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprintf(global.Output(), usage, config.DefaultPath) }
+	flags.register(global)
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	name, rest := "serve", global.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		global.Usage()
+		return 2
+	}
+
+	switch err := cmd(flags, rest); {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+}
+
+// newFlagSet returns the flags of a command, including --config and --set.
+func newFlagSet(name, args string, flags *sourceFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tarantool-app %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	flags.register(fs)
+	return fs
+}
+
+// parse parses the flags of a command. Invalid flags are reported by the
+// flag set itself.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+func serve(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("serve", "", flags)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return app.Run(flags.source())
+}
+
+func checkConfig(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("check-config", "", flags)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return app.CheckConfig(flags.source(), os.Stdout)
+}
+
+func migrate(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("migrate", "status | up [version] | down <version>", flags)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return app.Migrate(flags.source(), fs.Args(), os.Stdout)
+}
+
+func export(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("export", "", flags)
+	prefix := fs.String("prefix", "", "export keys starting with the prefix")
+	format := fs.String("format", "", "ndjson, csv or msgpack, guessed from --output by default")
+	output := fs.String("output", "-", "file to write, - for stdout")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return app.Export(flags.source(), *prefix, *format, *output)
+}
+
+func importValues(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("import", "[file]", flags)
+	var opts domain.ImportOptions
+	fs.StringVar(&opts.Format, "format", "", "ndjson, csv or msgpack, guessed from the file name by default")
+	fs.StringVar(&opts.Policy, "policy", domain.ConflictSkip, "existing keys: skip, overwrite or fail")
+	fs.IntVar(&opts.BatchSize, "batch", 0, "values written per batch (default 500)")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "report what would be written without writing")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	input := fs.Arg(0)
+
+	return app.Import(flags.source(), input, opts, os.Stdout)
+}
+
+func ping(flags *sourceFlags, args []string) error {
+	fs := newFlagSet("ping", "", flags)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return app.Ping(flags.source(), os.Stdout)
+}
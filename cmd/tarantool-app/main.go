@@ -1,7 +1,7 @@
 package main
 
 import (
-	"tarantool-app/internal/app"
+	"os"
 )
 
 // @title           Tarantool Key-Value API
@@ -14,7 +14,16 @@ import (
 // @host        localhost:8080
 // @BasePath    /
 
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        Authorization
+// @description                 Admin API token in the form "Bearer <token>".
+
+// @securityDefinitions.apikey  ServiceToken
+// @in                          header
+// @name                        Authorization
+// @description                 Service or admin token in the form "Bearer <token>", for locks and queues.
+
 func main() {
-	configPath := "app_config.yaml"
-	app.Run(configPath)
+	os.Exit(run(os.Args[1:]))
 }
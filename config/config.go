@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -10,35 +11,317 @@ import (
 // Note that `env` and `env-default` are config `cleanenv` package specific tags.
 
 type Config struct {
-	App        AppConfig        `yaml:"app"`
-	HTTPServer HTTPServerConfig `yaml:"http_server"`
-	Storage    Storage
+	App         AppConfig          `yaml:"app"`
+	HTTPServer  HTTPServerConfig   `yaml:"http_server"`
+	Log         LogConfig          `yaml:"log"`
+	Redaction   RedactionConfig    `yaml:"redaction"`
+	Storage     Storage            `yaml:"storage"`
+	Blob        BlobConfig         `yaml:"blob"`
+	Compression CompressionConfig  `yaml:"compression"`
+	Encryption  EncryptionConfig   `yaml:"encryption"`
+	History     HistoryConfig      `yaml:"history"`
+	Trash       TrashConfig        `yaml:"trash"`
+	Tiering     TieringConfig      `yaml:"tiering"`
+	Validation  ValidationConfig   `yaml:"validation"`
+	Auth        AuthConfig         `yaml:"auth"`
+	Indexes     []IndexConfig      `yaml:"indexes"`
+	Collections []CollectionConfig `yaml:"collections"`
+	SQL         SQLConfig          `yaml:"sql"`
+	Locks       LocksConfig        `yaml:"locks"`
+	Queues      QueuesConfig       `yaml:"queues"`
+	Migrations  MigrationsConfig   `yaml:"migrations"`
 }
 
+// AppConfig describes the application. LogLevel defaults to debug in the
+// local environment and info in prod. The config file is re-read every
+// ReloadInterval when it changed, zero disabling the check; see Reloadable.
 type AppConfig struct {
-	Environment string `yaml:"environment" env:"APP_ENV" env-default:"local" env-required:"true"`
-	Name        string `yaml:"name" env:"APP_NAME" env-required:"true"`
-	Version     string `yaml:"version" env:"APP_VERSION" env-required:"true"`
+	Environment    string        `yaml:"environment" env:"APP_ENV" env-default:"local" env-required:"true"`
+	Name           string        `yaml:"name" env:"APP_NAME" env-required:"true"`
+	Version        string        `yaml:"version" env:"APP_VERSION" env-required:"true"`
+	LogLevel       string        `yaml:"log_level" env:"LOG_LEVEL"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"CONFIG_RELOAD_INTERVAL"`
+}
+
+// Production reports whether the application runs in prod, "production"
+// being accepted as well for configs written before prod was introduced.
+func (a AppConfig) Production() bool {
+	return a.Environment == "prod" || a.Environment == "production"
+}
+
+// Outputs of LogConfig.
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+	LogOutputFile   = "file"
+)
+
+// LogConfig selects where logs are written. A log file is rotated once it
+// grows over MaxSizeMB, keeping MaxBackups old files for at most MaxAge.
+type LogConfig struct {
+	Output     string        `yaml:"output" env:"LOG_OUTPUT" env-default:"stderr"`
+	File       string        `yaml:"file" env:"LOG_FILE"`
+	MaxSizeMB  int           `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB" env-default:"100"`
+	MaxBackups int           `yaml:"max_backups" env:"LOG_MAX_BACKUPS" env-default:"5"`
+	MaxAge     time.Duration `yaml:"max_age" env:"LOG_MAX_AGE" env-default:"168h"`
+	Compress   bool          `yaml:"compress" env:"LOG_COMPRESS" env-default:"false"`
+}
+
+// RedactionConfig keeps customer data out of logs and error details.
+// Log fields named in Fields are replaced, keys under KeyPrefixes, or all
+// keys with HashKeys, are replaced by a hash salted with HashSalt, and any
+// other value longer than MaxValueLength is truncated.
+type RedactionConfig struct {
+	Disabled       bool     `yaml:"disabled" env:"REDACTION_DISABLED" env-default:"false"`
+	Fields         []string `yaml:"fields" env:"REDACTION_FIELDS" env-separator:"," env-default:"value,body,password,token,secret,authorization"`
+	KeyPrefixes    []string `yaml:"key_prefixes" env:"REDACTION_KEY_PREFIXES" env-separator:","`
+	HashKeys       bool     `yaml:"hash_keys" env:"REDACTION_HASH_KEYS" env-default:"false"`
+	HashSalt       string   `yaml:"hash_salt" env:"REDACTION_HASH_SALT"`
+	MaxValueLength int      `yaml:"max_value_length" env:"REDACTION_MAX_VALUE_LENGTH" env-default:"256"`
 }
 
 type HTTPServerConfig struct {
 	Port string `yaml:"port" env:"HTTP_PORT" env-default:"8080" env-required:"true"`
 }
 
+// BlobConfig controls values stored as chunks. Chunks of uploads not
+// committed within UploadTimeout are collected, as are those of replaced
+// or deleted values once Retention has passed, so that downloads already
+// streaming them can finish.
+type BlobConfig struct {
+	ChunkSize     int           `yaml:"chunk_size" env:"BLOB_CHUNK_SIZE" env-default:"262144"`
+	MaxSize       int64         `yaml:"max_size" env:"BLOB_MAX_SIZE" env-default:"268435456"`
+	UploadTimeout time.Duration `yaml:"upload_timeout" env:"BLOB_UPLOAD_TIMEOUT" env-default:"1h"`
+	Retention     time.Duration `yaml:"retention" env:"BLOB_RETENTION" env-default:"10m"`
+}
+
+// CompressionConfig controls compression of values in the repository layer.
+// Rules override the default algorithm for keys with the given prefix,
+// the longest matching prefix wins. Use algorithm "none" to disable.
+type CompressionConfig struct {
+	Enabled   bool              `yaml:"enabled" env:"COMPRESSION_ENABLED" env-default:"false"`
+	Algorithm string            `yaml:"algorithm" env:"COMPRESSION_ALGORITHM" env-default:"zstd"`
+	Threshold int               `yaml:"threshold" env:"COMPRESSION_THRESHOLD" env-default:"1024"`
+	Rules     []CompressionRule `yaml:"rules"`
+}
+
+type CompressionRule struct {
+	Prefix    string `yaml:"prefix"`
+	Algorithm string `yaml:"algorithm"`
+}
+
+// EncryptionConfig controls AES-GCM encryption of values. Keys with one of
+// the prefixes are encrypted, all keys are when no prefixes are given.
+type EncryptionConfig struct {
+	Enabled          bool          `yaml:"enabled" env:"ENCRYPTION_ENABLED" env-default:"false"`
+	KeyringFile      string        `yaml:"keyring_file" env:"ENCRYPTION_KEYRING_FILE"`
+	Prefixes         []string      `yaml:"prefixes" env:"ENCRYPTION_PREFIXES" env-separator:","`
+	RotationInterval time.Duration `yaml:"rotation_interval" env:"ENCRYPTION_ROTATION_INTERVAL" env-default:"1m"`
+	RotationBatch    int           `yaml:"rotation_batch" env:"ENCRYPTION_ROTATION_BATCH" env-default:"100"`
+}
+
+// HistoryConfig keeps the last Versions versions of every key, the current
+// one included. A replaced version is kept for at most MaxAge. Rules
+// override both for keys with the given prefix, the longest matching
+// prefix wins and zero settings of a rule are inherited.
+type HistoryConfig struct {
+	Enabled  bool          `yaml:"enabled" env:"HISTORY_ENABLED" env-default:"false"`
+	Versions int           `yaml:"versions" env:"HISTORY_VERSIONS" env-default:"10"`
+	MaxAge   time.Duration `yaml:"max_age" env:"HISTORY_MAX_AGE" env-default:"720h"`
+	Rules    []HistoryRule `yaml:"rules"`
+}
+
+type HistoryRule struct {
+	Prefix   string        `yaml:"prefix"`
+	Versions int           `yaml:"versions"`
+	MaxAge   time.Duration `yaml:"max_age"`
+}
+
+// TrashConfig moves deleted keys to a trash instead of removing them.
+// They can be undeleted for Retention, after which they are purged.
+type TrashConfig struct {
+	Enabled   bool          `yaml:"enabled" env:"TRASH_ENABLED" env-default:"false"`
+	Retention time.Duration `yaml:"retention" env:"TRASH_RETENTION" env-default:"168h"`
+}
+
+// TieringConfig keeps recently accessed keys in memory and demotes keys
+// idle for IdleAfter to disk. The leader sweeps the keys in memory every
+// Interval, Batch keys per request.
+type TieringConfig struct {
+	Enabled   bool          `yaml:"enabled" env:"TIERING_ENABLED" env-default:"false"`
+	IdleAfter time.Duration `yaml:"idle_after" env:"TIERING_IDLE_AFTER" env-default:"24h"`
+	Interval  time.Duration `yaml:"interval" env:"TIERING_INTERVAL" env-default:"1m"`
+	Batch     int           `yaml:"batch" env:"TIERING_BATCH" env-default:"1000"`
+}
+
+// ValidationConfig lists JSON Schema files enforced per key prefix.
+// Schemas registered through the admin API take precedence.
+type ValidationConfig struct {
+	Schemas         []SchemaFile  `yaml:"schemas"`
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"VALIDATION_REFRESH_INTERVAL" env-default:"30s"`
+}
+
+type SchemaFile struct {
+	Prefix string `yaml:"prefix"`
+	File   string `yaml:"file"`
+}
+
+// AuthConfig holds the bearer tokens guarding the /admin API and the
+// coordination APIs used by workers, /locks and /queues, which also accept
+// the admin token. An API is disabled while none of its tokens is set.
+type AuthConfig struct {
+	AdminToken   string `yaml:"admin_token" env:"ADMIN_TOKEN"`
+	ServiceToken string `yaml:"service_token" env:"SERVICE_TOKEN"`
+}
+
+// IndexConfig declares a secondary index on a path inside stored values.
+// The same section is read by `tt_init.lua` to create the indexes.
+type IndexConfig struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"` // dot-separated, "[*]" suffix for multikey indexes over arrays
+	Type string `yaml:"type"` // string, unsigned, integer, number or boolean
+}
+
+// CollectionConfig declares a collection, a space of values keyed by the
+// typed fields of Key instead of a string, e.g. (tenant, id). The same
+// section is read by `tt_init.lua` to create the spaces.
+type CollectionConfig struct {
+	Name string          `yaml:"name"`
+	Key  []KeyPartConfig `yaml:"key"`
+}
+
+type KeyPartConfig struct {
+	Field string `yaml:"field"`
+	Type  string `yaml:"type"` // string, unsigned, integer or uuid
+}
+
+// SQLConfig limits statements run through POST /admin/sql.
+// MaxSteps stops a statement in Tarantool once it executed that many
+// virtual machine instructions, whether or not the client still waits.
+type SQLConfig struct {
+	MaxRows  int           `yaml:"max_rows" env:"SQL_MAX_ROWS" env-default:"1000"`
+	Timeout  time.Duration `yaml:"timeout" env:"SQL_TIMEOUT" env-default:"5s"`
+	MaxSteps uint64        `yaml:"max_steps" env:"SQL_MAX_STEPS" env-default:"10000000"`
+}
+
+// LocksConfig bounds the leases of /locks. LeaderTTL is the lease used to
+// elect the instance running background jobs.
+type LocksConfig struct {
+	DefaultTTL time.Duration `yaml:"default_ttl" env:"LOCK_DEFAULT_TTL" env-default:"30s"`
+	MaxTTL     time.Duration `yaml:"max_ttl" env:"LOCK_MAX_TTL" env-default:"1h"`
+	LeaderTTL  time.Duration `yaml:"leader_ttl" env:"LOCK_LEADER_TTL" env-default:"15s"`
+}
+
+// QueuesConfig holds the defaults of the /queues API. Tasks taken
+// MaxAttempts times without an ack are buried as dead letters.
+type QueuesConfig struct {
+	Visibility    time.Duration `yaml:"visibility" env:"QUEUE_VISIBILITY" env-default:"30s"`
+	MaxVisibility time.Duration `yaml:"max_visibility" env:"QUEUE_MAX_VISIBILITY" env-default:"12h"`
+	MaxAttempts   int           `yaml:"max_attempts" env:"QUEUE_MAX_ATTEMPTS" env-default:"5"`
+	MaxWait       time.Duration `yaml:"max_wait" env:"QUEUE_MAX_WAIT" env-default:"30s"`
+}
+
+// MigrationsConfig controls the schema check on startup. With AutoApply
+// pending migrations are applied instead of refusing to serve. Timeout
+// bounds a single migrate run. Migrations are applied and reverted as the
+// Credentials user, the storage user may only read their status.
+type MigrationsConfig struct {
+	AutoApply   bool                 `yaml:"auto_apply" env:"MIGRATIONS_AUTO_APPLY" env-default:"false"`
+	Timeout     time.Duration        `yaml:"timeout" env:"MIGRATIONS_TIMEOUT" env-default:"10m"`
+	Credentials MigrationCredentials `yaml:"credentials"`
+}
+
+type MigrationCredentials struct {
+	Username string `yaml:"username" env:"TT_MIGRATIONS_USER"`
+	Password string `yaml:"password" env:"TT_MIGRATIONS_PASSWORD"`
+}
+
 type Storage struct {
-	Host        string `env:"TT_HOST" env-default:"tarantool-storage" env-required:"true"`
-	Port        string `env:"TT_PORT" env-default:"3301" env-required:"true"`
-	Credentials StorageCredentials
+	Host        string             `yaml:"host" env:"TT_HOST" env-default:"tarantool-storage" env-required:"true"`
+	Port        string             `yaml:"port" env:"TT_PORT" env-default:"3301" env-required:"true"`
+	Credentials StorageCredentials `yaml:"credentials"`
 }
 
 type StorageCredentials struct {
-	Username string `env:"TT_USER" env-required:"true"`
-	Password string `env:"TT_PASSWORD" env-required:"true"`
+	Username string `yaml:"username" env:"TT_USER" env-required:"true"`
+	Password string `yaml:"password" env:"TT_PASSWORD" env-required:"true"`
 }
 
-func Load(configPath string) (Config, error) {
+// DefaultPath is used when neither --config nor CONFIG_PATH is given.
+const DefaultPath = "app_config.yaml"
+
+// redacted replaces secrets in the output of Redacted.
+const redacted = "[REDACTED]"
+
+// Path picks the config file: the flag value, then the CONFIG_PATH
+// environment variable, then DefaultPath.
+func Path(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv("CONFIG_PATH"); env != "" {
+		return env
+	}
+	return DefaultPath
+}
+
+// Redacted returns a copy of the config safe to print, with the storage
+// passwords, the tokens and the redaction salt replaced.
+func (c Config) Redacted() Config {
+	if c.Storage.Credentials.Password != "" {
+		c.Storage.Credentials.Password = redacted
+	}
+	if c.Migrations.Credentials.Password != "" {
+		c.Migrations.Credentials.Password = redacted
+	}
+	if c.Auth.AdminToken != "" {
+		c.Auth.AdminToken = redacted
+	}
+	if c.Auth.ServiceToken != "" {
+		c.Auth.ServiceToken = redacted
+	}
+	if c.Redaction.HashSalt != "" {
+		c.Redaction.HashSalt = redacted
+	}
+	return c
+}
+
+// Migrator returns a copy of the config connecting to the storage as the
+// migrations user, or c itself when none is configured.
+func (c Config) Migrator() Config {
+	if c.Migrations.Credentials.Username != "" {
+		c.Storage.Credentials = StorageCredentials(c.Migrations.Credentials)
+	}
+	return c
+}
+
+// Reloadable lists the settings applied without a restart when the config
+// is reloaded, as YAML paths or prefixes of them.
+var Reloadable = []string{
+	"app.log_level",
+	"sql.",
+	"locks.default_ttl",
+	"locks.max_ttl",
+	"queues.",
+}
+
+// Load reads the config with Read and validates it.
+func Load(configPath string, overrides ...string) (Config, error) {
+	cfg, err := Read(configPath, overrides...)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Read reads the config file and applies environment variables and
+// overrides on top of it, without validating the result.
+func Read(configPath string, overrides ...string) (Config, error) {
 	if configPath == "" {
-		return Config{}, fmt.Errorf("CONFIG_PATH environment variable must be set")
+		return Config{}, fmt.Errorf("config path must be set with --config or CONFIG_PATH")
 	}
 
 	fileInfo, err := os.Stat(configPath)
@@ -53,7 +336,12 @@ func Load(configPath string) (Config, error) {
 	}
 
 	var cfg Config
-	err = cleanenv.ReadConfig(configPath, &cfg)
+	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+		return Config{}, err
+	}
 
-	return cfg, err
+	if err := cfg.applyOverrides(overrides); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
 }
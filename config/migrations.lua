@@ -0,0 +1,361 @@
+--- Versioned schema migrations, applied in order by kv_migrate_up and
+--- reverted in reverse order by kv_migrate_down. Append new steps with the
+--- next version and never change a step once it was released.
+---
+--- DDL cannot run in a transaction that yields, so steps are not atomic.
+--- Every step is idempotent instead: objects are created with
+--- if_not_exists and dropped only if they exist, so a step that failed
+--- midway is applied or reverted again from the start.
+---
+--- `once` names the box.once key that applied a step before migrations
+--- were introduced, so existing instances adopt the step without running it.
+
+-- Fields of `kv_storage` in the order they were added.
+local kv_storage_fields = {
+    { name = 'key', type = 'str' },
+    { name = 'value', type = 'any' },
+    { name = 'codec', type = 'string', is_nullable = true },
+    { name = 'kid', type = 'string', is_nullable = true },
+    { name = 'attrs', type = 'map', is_nullable = true },
+}
+
+--- Drops the space unless it is already gone, so that a step interrupted
+--- midway can be reverted or applied again.
+local function drop_space(name)
+    if box.space[name] ~= nil then
+        box.space[name]:drop()
+    end
+end
+
+local function drop_sequence(name)
+    if box.sequence[name] ~= nil then
+        box.sequence[name]:drop()
+    end
+end
+
+--- Sets the format of `kv_storage` to its first n fields.
+local function kv_storage_format(n)
+    local format = { unpack(kv_storage_fields, 1, n) }
+    if n == 2 then
+        format[2] = { name = 'value', type = 'map' }
+    end
+    box.space.kv_storage:format(format)
+end
+
+return {
+    {
+        version = 1,
+        name = 'bootstrap',
+        once = 'bootstrap',
+        up = function()
+            box.schema.space.create('kv_storage', { if_not_exists = true })
+            kv_storage_format(2)
+            box.space.kv_storage:create_index('primary', { parts = { 'key' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_storage')
+        end,
+    },
+    {
+        -- Large values are split into chunks. A value becomes visible only
+        -- after its metadata row in `kv_blobs` points to the generation of
+        -- its chunks.
+        version = 2,
+        name = 'blobs',
+        once = 'blobs',
+        up = function()
+            box.schema.space.create('kv_blobs', { if_not_exists = true })
+            box.space.kv_blobs:format({
+                { name = 'key', type = 'str' },
+                { name = 'generation', type = 'str' },
+                { name = 'size', type = 'unsigned' },
+                { name = 'chunk_size', type = 'unsigned' },
+                { name = 'chunks', type = 'unsigned' },
+                { name = 'content_type', type = 'str' },
+                { name = 'updated_at', type = 'unsigned' },
+            })
+            box.space.kv_blobs:create_index('primary', { parts = { 'key' }, if_not_exists = true })
+
+            box.schema.space.create('kv_chunks', { if_not_exists = true })
+            box.space.kv_chunks:format({
+                { name = 'key', type = 'str' },
+                { name = 'generation', type = 'str' },
+                { name = 'seq', type = 'unsigned' },
+                { name = 'data', type = 'varbinary' },
+            })
+            box.space.kv_chunks:create_index('primary', { parts = { 'key', 'generation', 'seq' }, if_not_exists = true })
+
+            -- Generations of blobs: uploads in progress, the live
+            -- generation of every blob, whose expires_at is null, and
+            -- replaced or deleted generations kept for downloads in
+            -- flight. Chunks of expired generations are collected.
+            box.schema.space.create('kv_blob_generations', { if_not_exists = true })
+            box.space.kv_blob_generations:format({
+                { name = 'key', type = 'str' },
+                { name = 'generation', type = 'str' },
+                { name = 'started_at', type = 'unsigned' }, -- milliseconds since the epoch
+                { name = 'expires_at', type = 'unsigned', is_nullable = true },
+            })
+            box.space.kv_blob_generations:create_index('primary', {
+                parts = { 'key', 'generation' }, if_not_exists = true,
+            })
+            box.space.kv_blob_generations:create_index('expires_at', {
+                unique = false,
+                if_not_exists = true,
+                parts = { { field = 'expires_at', type = 'unsigned', is_nullable = true, exclude_null = true } },
+            })
+        end,
+        down = function()
+            drop_space('kv_blob_generations')
+            drop_space('kv_chunks')
+            drop_space('kv_blobs')
+        end,
+    },
+    {
+        -- Values may be stored compressed as binary, the codec field names
+        -- the algorithm.
+        version = 3,
+        name = 'value_codec',
+        once = 'value_codec',
+        up = function()
+            kv_storage_format(3)
+        end,
+        down = function()
+            kv_storage_format(2)
+        end,
+    },
+    {
+        -- Encrypted values carry the id of the key they are sealed with.
+        version = 4,
+        name = 'value_encryption',
+        once = 'value_encryption',
+        up = function()
+            kv_storage_format(4)
+
+            -- Progress of key rotation per space: the active key a pass
+            -- re-encrypts with, the primary key it stopped after and
+            -- whether it completed, so a pass resumes instead of
+            -- rescanning.
+            box.schema.space.create('kv_rotation', { if_not_exists = true })
+            box.space.kv_rotation:format({
+                { name = 'space', type = 'str' },
+                { name = 'kid', type = 'str' },
+                { name = 'after', type = 'array' },
+                { name = 'done', type = 'boolean' },
+            })
+            box.space.kv_rotation:create_index('primary', { parts = { 'space' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_rotation')
+            kv_storage_format(3)
+        end,
+    },
+    {
+        -- JSON Schemas registered through the admin API, keyed by key prefix.
+        version = 5,
+        name = 'schemas',
+        once = 'schemas',
+        up = function()
+            box.schema.space.create('kv_schemas', { if_not_exists = true })
+            box.space.kv_schemas:format({
+                { name = 'prefix', type = 'str' },
+                { name = 'schema', type = 'map' },
+            })
+            box.space.kv_schemas:create_index('primary', { parts = { 'prefix' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_schemas')
+        end,
+    },
+    {
+        -- Plain projection of the indexed paths of a value, see the
+        -- `indexes` section of app_config.yaml.
+        version = 6,
+        name = 'value_attrs',
+        once = 'value_attrs',
+        up = function()
+            kv_storage_format(5)
+        end,
+        down = function()
+            for _, index in pairs(box.space.kv_storage.index) do
+                if index.name ~= 'primary' then
+                    index:drop()
+                end
+            end
+            kv_storage_format(4)
+        end,
+    },
+    {
+        -- Leases for distributed locks. Fencing tokens come from a
+        -- sequence, so they keep increasing across releases and expirations.
+        version = 7,
+        name = 'locks',
+        once = 'locks',
+        up = function()
+            box.schema.space.create('kv_locks', { if_not_exists = true })
+            box.space.kv_locks:format({
+                { name = 'name', type = 'str' },
+                { name = 'owner', type = 'str' },
+                { name = 'token', type = 'unsigned' },
+                { name = 'expires_at', type = 'unsigned' }, -- milliseconds since the epoch
+            })
+            box.space.kv_locks:create_index('primary', { parts = { 'name' }, if_not_exists = true })
+            box.space.kv_locks:create_index('expires_at', { parts = { 'expires_at' }, unique = false, if_not_exists = true })
+            box.schema.sequence.create('kv_lock_tokens', { min = 1, start = 1, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_locks')
+            drop_sequence('kv_lock_tokens')
+        end,
+    },
+    {
+        -- Tasks of the work queues. Ready tasks become visible at ready_at,
+        -- taken tasks are redelivered once ready_at, their visibility
+        -- deadline, passes.
+        version = 8,
+        name = 'queues',
+        once = 'queues',
+        up = function()
+            box.schema.sequence.create('kv_queue_ids', { min = 1, start = 1, if_not_exists = true })
+            box.schema.space.create('kv_queue_tasks', { if_not_exists = true })
+            box.space.kv_queue_tasks:format({
+                { name = 'id', type = 'unsigned' },
+                { name = 'queue', type = 'str' },
+                { name = 'status', type = 'str' }, -- ready, taken or buried
+                { name = 'ready_at', type = 'unsigned' }, -- milliseconds since the epoch
+                { name = 'attempts', type = 'unsigned' },
+                { name = 'created_at', type = 'unsigned' },
+                { name = 'payload', type = 'any' },
+            })
+            box.space.kv_queue_tasks:create_index('primary', { parts = { 'id' }, sequence = 'kv_queue_ids', if_not_exists = true })
+            box.space.kv_queue_tasks:create_index('queue_status', { parts = { 'queue', 'status', 'ready_at', 'id' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_queue_tasks')
+            drop_sequence('kv_queue_ids')
+        end,
+    },
+    {
+        -- Append-only trail of changes of stored values, written by a
+        -- trigger on `kv_storage` in the transaction of the change.
+        version = 9,
+        name = 'audit',
+        up = function()
+            box.schema.sequence.create('kv_audit_ids', { min = 1, start = 1, if_not_exists = true })
+            box.schema.space.create('kv_audit', { if_not_exists = true })
+            box.space.kv_audit:format({
+                { name = 'id', type = 'unsigned' },
+                { name = 'key', type = 'str' },
+                { name = 'op', type = 'str' }, -- create, update or delete
+                { name = 'at', type = 'unsigned' }, -- milliseconds since the epoch
+                { name = 'principal', type = 'str' },
+                { name = 'client_ip', type = 'str' },
+                { name = 'request_id', type = 'str' },
+                { name = 'old_hash', type = 'string', is_nullable = true },
+                { name = 'new_hash', type = 'string', is_nullable = true },
+            })
+            box.space.kv_audit:create_index('primary', { parts = { 'id' }, sequence = 'kv_audit_ids', if_not_exists = true })
+            box.space.kv_audit:create_index('key', { parts = { 'key', 'id' }, if_not_exists = true })
+            box.space.kv_audit:create_index('at', { parts = { 'at', 'id' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_audit')
+            drop_sequence('kv_audit_ids')
+        end,
+    },
+    {
+        -- Versions of stored values kept for restores, written by a trigger
+        -- on `kv_storage`. Deleted keys leave a version without a value.
+        -- A version expires once it was replaced for its maximum age.
+        version = 10,
+        name = 'history',
+        up = function()
+            box.schema.space.create('kv_history', { if_not_exists = true })
+            box.space.kv_history:format({
+                { name = 'key', type = 'str' },
+                { name = 'version', type = 'unsigned' },
+                { name = 'at', type = 'unsigned' }, -- milliseconds since the epoch
+                { name = 'expires_at', type = 'unsigned', is_nullable = true },
+                { name = 'value', type = 'any', is_nullable = true },
+                { name = 'codec', type = 'string', is_nullable = true },
+                { name = 'kid', type = 'string', is_nullable = true },
+                { name = 'attrs', type = 'map', is_nullable = true },
+            })
+            box.space.kv_history:create_index('primary', { parts = { 'key', 'version' }, if_not_exists = true })
+            box.space.kv_history:create_index('expires_at', {
+                unique = false,
+                if_not_exists = true,
+                parts = { { field = 'expires_at', type = 'unsigned', is_nullable = true, exclude_null = true } },
+            })
+        end,
+        down = function()
+            drop_space('kv_history')
+        end,
+    },
+    {
+        -- Keys deleted in soft-delete mode, restorable until they expire.
+        version = 11,
+        name = 'trash',
+        up = function()
+            box.schema.space.create('kv_trash', { if_not_exists = true })
+            box.space.kv_trash:format({
+                { name = 'key', type = 'str' },
+                { name = 'value', type = 'any' },
+                { name = 'codec', type = 'string', is_nullable = true },
+                { name = 'kid', type = 'string', is_nullable = true },
+                { name = 'attrs', type = 'map', is_nullable = true },
+                { name = 'deleted_at', type = 'unsigned' }, -- milliseconds since the epoch
+                { name = 'expires_at', type = 'unsigned' },
+                { name = 'principal', type = 'str' },
+                { name = 'request_id', type = 'str' },
+            })
+            box.space.kv_trash:create_index('primary', { parts = { 'key' }, if_not_exists = true })
+            box.space.kv_trash:create_index('expires_at', { parts = { 'expires_at' }, unique = false, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_trash')
+        end,
+    },
+    {
+        -- Collections defined over HTTP, created and dropped by
+        -- kv_collection_create and kv_collection_drop.
+        version = 12,
+        name = 'collections',
+        up = function()
+            box.schema.space.create('kv_collections', { if_not_exists = true })
+            box.space.kv_collections:format({
+                { name = 'name', type = 'str' },
+                { name = 'engine', type = 'str' },
+                { name = 'key', type = 'array' }, -- of { field, type }
+                { name = 'indexes', type = 'array' }, -- of { name, path, type, unique }
+                { name = 'created_at', type = 'unsigned' }, -- milliseconds since the epoch
+            })
+            box.space.kv_collections:create_index('primary', { parts = { 'name' }, if_not_exists = true })
+        end,
+        down = function()
+            drop_space('kv_collections')
+        end,
+    },
+    {
+        -- Keys demoted to disk by the tiering, with the fields of
+        -- `kv_storage`. Reverting moves them back to memory first.
+        version = 13,
+        name = 'tiering',
+        up = function()
+            box.schema.space.create('kv_cold', { engine = 'vinyl', if_not_exists = true })
+            box.space.kv_cold:format(kv_storage_fields)
+            box.space.kv_cold:create_index('primary', { parts = { 'key' }, if_not_exists = true })
+        end,
+        down = function()
+            if box.space.kv_cold == nil then
+                return
+            end
+            for _, tuple in box.space.kv_cold:pairs() do
+                if box.space.kv_storage:get({ tuple.key }) == nil then
+                    box.space.kv_storage:insert(tuple)
+                end
+            end
+            drop_space('kv_cold')
+        end,
+    },
+}
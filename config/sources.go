@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source tells where the config comes from. Layers are applied in order:
+// `env-default` tags, the YAML file, environment variables, then
+// Overrides given as "path=value" on the command line.
+type Source struct {
+	Path      string
+	Overrides []string
+}
+
+func (s Source) Load() (Config, error) {
+	return Load(s.Path, s.Overrides...)
+}
+
+// Update implements cleanenv.Updater. It runs before environment variables
+// are read and fills string fields from the files named by their `_FILE`
+// variants, e.g. TT_PASSWORD_FILE for Docker secrets. The variable itself
+// still takes precedence.
+func (c *Config) Update() error {
+	return readSecretFiles(reflect.ValueOf(c).Elem())
+}
+
+func readSecretFiles(v reflect.Value) error {
+	var errs []error
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+
+		if value.Kind() == reflect.Struct {
+			errs = append(errs, readSecretFiles(value))
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		if name == "" || value.Kind() != reflect.String {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		file, ok := os.LookupEnv(name + "_FILE")
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", name, err))
+			continue
+		}
+		value.SetString(strings.TrimRight(string(data), "\r\n"))
+	}
+	return errors.Join(errs...)
+}
+
+// applyOverrides sets the fields addressed by the dot-separated YAML paths
+// of "path=value" overrides. Values are parsed as YAML, so durations, lists
+// and maps are written as in the config file.
+func (c *Config) applyOverrides(overrides []string) error {
+	var errs []error
+	for _, override := range overrides {
+		path, value, ok := strings.Cut(override, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("override %q: expected path=value", override))
+			continue
+		}
+
+		field, err := fieldByPath(reflect.ValueOf(c).Elem(), path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("override %q: %w", override, err))
+			continue
+		}
+
+		parsed := reflect.New(field.Type())
+		if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("override %q: %w", override, err))
+			continue
+		}
+		field.Set(parsed.Elem())
+	}
+	return errors.Join(errs...)
+}
+
+// fieldByPath finds a field by the YAML names of the structs leading to it.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", path)
+		}
+		found := false
+		for i := range v.NumField() {
+			if yamlName(v.Type().Field(i)) == name {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", path)
+		}
+	}
+	return v, nil
+}
+
+// yamlName is the name of a field in the config file.
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// Changed lists the YAML paths of settings that differ between c and
+// other, e.g. "sql.max_rows". Lists are compared as a whole.
+func (c Config) Changed(other Config) []string {
+	return changedPaths(reflect.ValueOf(c), reflect.ValueOf(other), "")
+}
+
+func changedPaths(a, b reflect.Value, prefix string) []string {
+	var changed []string
+	for i := range a.NumField() {
+		path := prefix + yamlName(a.Type().Field(i))
+		fa, fb := a.Field(i), b.Field(i)
+
+		if fa.Kind() == reflect.Struct {
+			changed = append(changed, changedPaths(fa, fb, path+".")...)
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
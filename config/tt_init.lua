@@ -3,24 +3,1343 @@
 -- Import required modules
 local box = require('box')
 local msgpack = require('msgpack')
+local clock = require('clock')
+local fio = require('fio')
+local yaml = require('yaml')
+local ffi = require('ffi')
+local fiber = require('fiber')
+local digest = require('digest')
+local key_def = require('key_def')
 
--- Runs only once during the initialization.
-box.once("bootstrap", function()
-    --- Create a space.
-    box.schema.space.create('kv_storage')
+--- MsgPack serialization option.
+msgpack.cfg{
+    encode_invalid_as_nil = true,
+}
 
+local script_dir = fio.dirname(debug.sourcefile() or fio.pathjoin(fio.cwd(), 'tt_init.lua'))
 
-    --- Define schema.
-    box.space.kv_storage:format({
-        { name = 'key', type = 'str' },
-        { name = 'value', type = 'map' },
+--- Ordered schema migrations, see migrations.lua.
+local migrations = dofile(fio.pathjoin(script_dir, 'migrations.lua'))
+
+--- The parsed app_config.yaml, empty when missing.
+local function app_config()
+    local file = fio.open(fio.pathjoin(script_dir, 'app_config.yaml'), { 'O_RDONLY' })
+    if file == nil then
+        return {}
+    end
+    local cfg = yaml.decode(file:read())
+    file:close()
+    return cfg or {}
+end
+
+--- Secondary indexes declared in the `indexes` section of app_config.yaml.
+local function declared_indexes()
+    return app_config().indexes or {}
+end
+
+--- Fails when an existing index of the space differs from its declaration:
+--- if_not_exists would silently keep the old path or type.
+local function check_declared_index(space, index)
+    local existing = space.index[index.name]
+    if existing == nil then
+        return
+    end
+    local part = existing.parts[1]
+    local path = part.path and part.path:gsub('^%.', '') or nil
+    -- attrs is the fifth field.
+    if #existing.parts ~= 1 or part.fieldno ~= 5 or path ~= index.path or part.type ~= index.type then
+        error(string.format(
+            'index %s of %s is on %s %s, declared on %s %s: drop the index to change it',
+            index.name, space.name, tostring(path), tostring(part.type), index.path, index.type))
+    end
+end
+
+--- Creates the declared indexes once `kv_storage` has the `attrs` field.
+local function create_declared_indexes()
+    local space = box.space.kv_storage
+    if space == nil or #space:format() < 5 then
+        return
+    end
+    for _, index in ipairs(declared_indexes()) do
+        check_declared_index(space, index)
+        local multikey = index.path:find('[*]', 1, true) ~= nil
+        space:create_index(index.name, {
+            unique = false,
+            if_not_exists = true,
+            parts = { {
+                field = 'attrs',
+                path = index.path,
+                type = index.type,
+                is_nullable = true,
+                exclude_null = not multikey,
+            } },
+        })
+    end
+end
+
+--- Creates the space of a collection, a map value keyed by typed fields
+--- with secondary indexes over paths inside the value, and lets the
+--- application user read and write it. An existing space is left as is.
+local function create_collection(name, engine, key, indexes)
+    local format, parts = {}, {}
+    for _, part in ipairs(key) do
+        table.insert(format, { name = part.field, type = part.type })
+        table.insert(parts, part.field)
+    end
+    table.insert(format, { name = 'value', type = 'map' })
+
+    local space = box.schema.space.create(name, { engine = engine, format = format, if_not_exists = true })
+    space:create_index('primary', { parts = parts, if_not_exists = true })
+    for _, index in ipairs(indexes or {}) do
+        space:create_index(index.name, {
+            unique = index.unique == true,
+            if_not_exists = true,
+            parts = { { field = 'value', path = index.path, type = index.type, is_nullable = true } },
+        })
+    end
+
+    local user = os.getenv('TT_USER')
+    if user ~= nil and user ~= '' then
+        box.schema.user.grant(user, 'read,write', 'space', name, { if_not_exists = true })
+    end
+end
+
+--- Creates the collections declared in the `collections` section of
+--- app_config.yaml.
+local function create_declared_collections()
+    for _, collection in ipairs(app_config().collections or {}) do
+        create_collection(collection.name, 'memtx', collection.key)
+    end
+end
+
+local function now_ms()
+    return math.floor(fiber.time() * 1000)
+end
+
+--- Error code raised by the blob functions, mirrored in the repository.
+local ERR_BLOB_INCOMPLETE = 10001
+
+--- Chunks deleted per transaction when collecting a generation.
+local BLOB_GC_BATCH = 100
+
+--- Returns the row of a blob generation in `kv_blob_generations` unless
+--- it expired: an upload in progress or a live generation, whose rows
+--- never expire.
+local function live_generation(key, generation)
+    local row = box.space.kv_blob_generations:get({ key, generation })
+    if row == nil or (row.expires_at ~= nil and row.expires_at <= now_ms()) then
+        return nil
+    end
+    return row
+end
+
+--- Lets the chunks of a generation be collected after retention_ms.
+local function retire_generation(key, generation, retention_ms)
+    local row = box.space.kv_blob_generations:get({ key, generation })
+    box.space.kv_blob_generations:replace({
+        key, generation, row ~= nil and row.started_at or now_ms(), now_ms() + retention_ms,
     })
+end
+
+--- Registers an upload of a new generation, collected unless it is
+--- committed within timeout_ms.
+function kv_blob_begin(key, generation, timeout_ms)
+    local now = now_ms()
+    return box.space.kv_blob_generations:insert({ key, generation, now, now + timeout_ms })
+end
+
+--- Stores a chunk of an upload that has not expired.
+function kv_blob_put_chunk(key, generation, seq, data)
+    return box.atomic(function()
+        if live_generation(key, generation) == nil then
+            box.error({ code = ERR_BLOB_INCOMPLETE, reason = 'blob upload expired' })
+        end
+        return box.space.kv_chunks:insert({ key, generation, seq, data })
+    end)
+end
+
+--- Publishes an uploaded generation. The previous one is kept for
+--- retention_ms, so that downloads already streaming it can finish.
+function kv_blob_commit(key, generation, size, chunk_size, chunks, content_type, retention_ms)
+    return box.atomic(function()
+        if live_generation(key, generation) == nil then
+            box.error({ code = ERR_BLOB_INCOMPLETE, reason = 'blob upload expired' })
+        end
+        local uploaded = box.space.kv_chunks.index.primary:count({ key, generation })
+        if uploaded ~= chunks then
+            box.error({ code = ERR_BLOB_INCOMPLETE, reason = 'blob chunks are incomplete' })
+        end
+        box.space.kv_blob_generations:update({ key, generation }, { { '=', 'expires_at', box.NULL } })
+
+        local old = box.space.kv_blobs:get({ key })
+        local meta = box.space.kv_blobs:replace({
+            key, generation, size, chunk_size, chunks, content_type, math.floor(clock.time()),
+        })
+        if old ~= nil and old.generation ~= generation then
+            retire_generation(key, old.generation, retention_ms)
+        end
+        return meta
+    end)
+end
+
+--- Lets the chunks of an upload that did not complete be collected.
+function kv_blob_abort(key, generation)
+    return box.atomic(function()
+        local meta = box.space.kv_blobs:get({ key })
+        if meta ~= nil and meta.generation == generation then
+            return
+        end
+        retire_generation(key, generation, 0)
+    end)
+end
 
-    --- Add primary index.
-    box.space.kv_storage:create_index('primary', { parts = { 'key' } })
+--- Deletes a blob. Its chunks are kept for retention_ms, like those of a
+--- replaced generation.
+function kv_blob_delete(key, retention_ms)
+    return box.atomic(function()
+        local meta = box.space.kv_blobs:delete({ key })
+        if meta ~= nil then
+            retire_generation(key, meta.generation, retention_ms)
+        end
+        return meta
+    end)
+end
 
-    --- MsgPack serialization option.
-    msgpack.cfg{
-        encode_invalid_as_nil = true,
-    }
+--- Deletes the chunks of an expired generation, a batch per transaction,
+--- and then the generation itself. Expired generations cannot be
+--- committed again, so no chunk is added meanwhile.
+local function collect_generation(key, generation)
+    local primary = box.space.kv_chunks.index.primary
+    while true do
+        local chunks = primary:select({ key, generation }, { limit = BLOB_GC_BATCH })
+        if #chunks == 0 then
+            break
+        end
+        box.atomic(function()
+            for _, chunk in ipairs(chunks) do
+                box.space.kv_chunks:delete({ key, generation, chunk.seq })
+            end
+        end)
+    end
+    box.space.kv_blob_generations:delete({ key, generation })
+end
+
+--- Collects uploads that were never committed and generations replaced or
+--- deleted longer than their retention ago.
+fiber.create(function()
+    fiber.name('kv_blob_gc')
+    while true do
+        fiber.sleep(10)
+        if not box.info.ro and box.space.kv_blob_generations ~= nil then
+            local expired = box.space.kv_blob_generations.index.expires_at:select(
+                { now_ms() }, { iterator = 'LE', limit = BLOB_GC_BATCH })
+            for _, row in ipairs(expired) do
+                collect_generation(row.key, row.generation)
+            end
+        end
+    end
 end)
+
+--- Keys are tiered between `kv_storage` in memory and `kv_cold` on disk.
+--- Tarantool has no transactions across engines, so a key is moved by
+--- copying it first and removing the source only if it did not change
+--- meanwhile: a key may be in both tiers for a moment, the hot copy wins.
+
+--- Set once the application reads keys with tracking or demotes them,
+--- tiering being enabled.
+local tiering = false
+
+--- Hot keys whose last access is tracked while tiering is enabled, at
+--- most TRACKED_KEYS of them. The least recently accessed key is forgotten
+--- to make room for another.
+local TRACKED_KEYS = 100000
+
+--- Tracked keys by key, nodes of a list from the most recently accessed
+--- key, head.next, to the least recently accessed one, head.prev. A node
+--- holds the key and the time of its last access in milliseconds.
+local tracked, tracked_count = {}, 0
+local head = {}
+head.prev, head.next = head, head
+
+--- Time of the last access to the keys that are not tracked: when the
+--- application started, e.g. for keys not accessed since a restart, or of
+--- the last key forgotten, which was accessed later than every key
+--- forgotten before.
+local untracked_since = now_ms()
+
+local function unlink(node)
+    node.prev.next, node.next.prev = node.next, node.prev
+end
+
+--- Records an access to the key at now.
+local function touch(key, now)
+    local node = tracked[key]
+    if node ~= nil then
+        unlink(node)
+    else
+        if tracked_count >= TRACKED_KEYS then
+            local oldest = head.prev
+            unlink(oldest)
+            tracked[oldest.key], tracked_count = nil, tracked_count - 1
+            untracked_since = math.max(untracked_since, oldest.at)
+        end
+        node = { key = key }
+        tracked[key], tracked_count = node, tracked_count + 1
+    end
+    node.at, node.prev, node.next = now, head, head.next
+    head.next.prev, head.next = node, node
+end
+
+--- Stops tracking the key, e.g. once it is demoted.
+local function forget(key)
+    local node = tracked[key]
+    if node ~= nil then
+        unlink(node)
+        tracked[key], tracked_count = nil, tracked_count - 1
+    end
+end
+
+--- Returns the time of the last access to the key.
+local function last_access(key)
+    local node = tracked[key]
+    return node ~= nil and node.at or untracked_since
+end
+
+--- Latches of the keys being moved between tiers or changed, by key: the
+--- fiber holding the latch, how many times it took it, and a condition
+--- the fibers waiting for the latch wait on.
+local latches = {}
+
+--- Runs fn(...) holding the latch of the key, so that moves and changes of
+--- the key do not interleave while they yield, e.g. on `kv_cold` reads or
+--- WAL writes. The fiber holding the latch may take it again.
+local function with_latch(key, fn, ...)
+    local self = fiber.self():id()
+    local latch = latches[key]
+    while latch ~= nil and latch.owner ~= self do
+        latch.cond:wait()
+        latch = latches[key]
+    end
+    if latch == nil then
+        latch = { owner = self, depth = 0, cond = fiber.cond() }
+        latches[key] = latch
+    end
+
+    latch.depth = latch.depth + 1
+    local ok, result = pcall(fn, ...)
+    latch.depth = latch.depth - 1
+    if latch.depth == 0 then
+        latches[key] = nil
+        latch.cond:broadcast()
+    end
+    if not ok then
+        error(result)
+    end
+    return result
+end
+
+--- Runs fn without recording its changes in the audit trail and the
+--- history, for changes leaving values as they are: moving a key between
+--- tiers, re-encrypting it.
+local function unrecorded(fn, ...)
+    local storage = fiber.self().storage
+    storage.kv_unrecorded = true
+    local ok, result = pcall(fn, ...)
+    storage.kv_unrecorded = nil
+    if not ok then
+        error(result)
+    end
+    return result
+end
+
+--- Reports whether both tuples are the same.
+local function same_tuple(a, b)
+    return a ~= nil and b ~= nil and msgpack.encode(a) == msgpack.encode(b)
+end
+
+--- Deletes the cold tuple of the key if it is still tuple.
+local function delete_cold(key, tuple)
+    box.atomic(function()
+        if same_tuple(box.space.kv_cold:get({ key }), tuple) then
+            box.space.kv_cold:delete({ key })
+        end
+    end)
+end
+
+--- Moves the key back from `kv_cold` unless it is hot already, and returns
+--- its hot tuple. Callers hold the latch of the key.
+local function promote(key)
+    local hot = box.space.kv_storage:get({ key })
+    if hot ~= nil or box.space.kv_cold == nil then
+        return hot
+    end
+    local cold = box.space.kv_cold:get({ key })
+    if cold == nil then
+        return nil
+    end
+
+    -- The vinyl read yielded: the key may have been written meanwhile.
+    hot = unrecorded(function()
+        return box.space.kv_storage:get({ key }) or box.space.kv_storage:insert(cold)
+    end)
+    delete_cold(key, cold)
+    return hot
+end
+
+--- Moves the hot tuple to `kv_cold` unless it changed since it was read,
+--- and returns whether it was moved.
+local function demote(tuple)
+    return with_latch(tuple.key, function()
+        if not same_tuple(box.space.kv_storage:get({ tuple.key }), tuple) then
+            return false
+        end
+        box.space.kv_cold:replace(tuple)
+
+        -- The vinyl write yielded: check the hot tuple again.
+        local moved = unrecorded(box.atomic, function()
+            if not same_tuple(box.space.kv_storage:get({ tuple.key }), tuple) then
+                return false
+            end
+            box.space.kv_storage:delete({ tuple.key })
+            return true
+        end)
+
+        if moved then
+            forget(tuple.key)
+        else
+            delete_cold(tuple.key, tuple)
+        end
+        return moved
+    end)
+end
+
+--- Updates the tuple of the key with ops in the tier holding it if matches
+--- accepts the tuple, and returns whether it was updated. A cold key stays
+--- on disk.
+local function update_in_tier(key, matches, ops)
+    return with_latch(key, function()
+        for _, space in ipairs({ box.space.kv_storage, box.space.kv_cold }) do
+            local updated = box.atomic(function()
+                local current = space:get({ key })
+                if current == nil then
+                    return nil
+                end
+                if not matches(current) then
+                    return false
+                end
+                space:update({ key }, ops)
+                return true
+            end)
+            if updated ~= nil then
+                return updated
+            end
+        end
+        return false
+    end)
+end
+
+--- Returns the tuple of the key from either tier, promoting a cold key,
+--- and records the access when track is set.
+function kv_tier_get(key, track)
+    local tuple = with_latch(key, promote, key)
+    if tuple == nil then
+        return
+    end
+    if track then
+        tiering = true
+        touch(key, now_ms())
+    end
+    return tuple
+end
+
+--- Sweeps up to limit hot keys after the key after, '' for the first, and
+--- demotes those not accessed for idle_ms. Returns the number of keys
+--- demoted and the last key swept, '' once the sweep is complete.
+function kv_tier_demote(idle_ms, after, limit)
+    local tuples = box.space.kv_storage.index.primary:select({ after }, { iterator = 'GT', limit = limit })
+    local now, demoted = now_ms(), 0
+    tiering = true
+    for _, tuple in ipairs(tuples) do
+        if now - last_access(tuple.key) >= idle_ms and demote(tuple) then
+            demoted = demoted + 1
+        end
+    end
+
+    if #tuples < limit then
+        return demoted, ''
+    end
+    return demoted, tuples[#tuples].key
+end
+
+--- Returns the first tuple of either tier from start on.
+local function first_tuple(start, iterator)
+    local hot = box.space.kv_storage.index.primary:select({ start }, { iterator = iterator, limit = 1 })[1]
+    if box.space.kv_cold == nil then
+        return hot
+    end
+    local cold = box.space.kv_cold.index.primary:select({ start }, { iterator = iterator, limit = 1 })[1]
+    if hot == nil or (cold ~= nil and cold.key < hot.key) then
+        return cold
+    end
+    return hot
+end
+
+--- Reports whether the key is stored in either tier.
+local function is_stored(key)
+    return box.space.kv_storage:get({ key }) ~= nil
+        or (box.space.kv_cold ~= nil and box.space.kv_cold:get({ key }) ~= nil)
+end
+
+--- Replaces a stored value only if it was not modified since it was read,
+--- in the tier holding it. Used for background re-encryption with a new
+--- key and for counters of encoded values.
+function kv_swap_value(key, old_kid, old_value, value, codec, kid)
+    return update_in_tier(key, function(current)
+        return current.kid == old_kid and msgpack.encode(current.value) == msgpack.encode(old_value)
+    end, {
+        { '=', 'value', value },
+        { '=', 'codec', codec },
+        { '=', 'kid', kid },
+    })
+end
+
+--- Spaces holding values sealed by the application, re-encrypted by
+--- kv_reseal_value.
+local sealed_spaces = { kv_storage = true, kv_history = true, kv_trash = true }
+
+--- Replaces a value of the space re-encrypted with another key unless it
+--- changed since it was read, and returns whether it was replaced. The
+--- plain value is left as is, so the change is not recorded.
+function kv_reseal_value(space, primary_key, old_kid, old_value, value, codec, kid)
+    if not sealed_spaces[space] or box.space[space] == nil then
+        box.error(box.error.ILLEGAL_PARAMS, 'space ' .. tostring(space) .. ' holds no sealed values')
+    end
+    if space == 'kv_storage' then
+        return unrecorded(kv_swap_value, primary_key[1], old_kid, old_value, value, codec, kid)
+    end
+
+    return box.atomic(function()
+        local current = box.space[space]:get(primary_key)
+        if current == nil or current.kid ~= old_kid then
+            return false
+        end
+        if msgpack.encode(current.value) ~= msgpack.encode(old_value) then
+            return false
+        end
+        box.space[space]:update(primary_key, {
+            { '=', 'value', value },
+            { '=', 'codec', codec },
+            { '=', 'kid', kid },
+        })
+        return true
+    end)
+end
+
+--- Sets the indexed projection of a value unless the value changed since
+--- it was read, in the tier holding it.
+function kv_set_attrs(key, old_value, attrs)
+    return update_in_tier(key, function(current)
+        return msgpack.encode(current.value) == msgpack.encode(old_value)
+    end, { { '=', 'attrs', attrs } })
+end
+
+local function has_prefix(s, prefix)
+    return s:sub(1, #prefix) == prefix
+end
+
+--- Returns up to limit immediate children of the path whose keys start
+--- with prefix, e.g. 'tenant/' or '' for the root, in key order after the
+--- child named after. Each child is { name, key, has_value, has_children }.
+--- Subtrees are skipped with a single lookup, '0' sorting right after '/'.
+function kv_children(prefix, after, limit)
+    local children = {}
+    local start, iterator = prefix, 'GE'
+    if after ~= nil and after ~= '' then
+        start, iterator = prefix .. after, 'GT'
+    end
+
+    while #children < limit do
+        local tuple = first_tuple(start, iterator)
+        if tuple == nil or not has_prefix(tuple.key, prefix) then
+            break
+        end
+
+        local rest = tuple.key:sub(#prefix + 1)
+        local slash = rest:find('/', 1, true)
+        if slash == nil then
+            -- The key sorts before its subtree, which is looked up ahead.
+            local below = first_tuple(tuple.key .. '/', 'GE')
+            local has_children = below ~= nil and has_prefix(below.key, tuple.key .. '/')
+            table.insert(children, { rest, tuple.key, true, has_children })
+            start, iterator = tuple.key, 'GT'
+        else
+            local name = rest:sub(1, slash - 1)
+            if name ~= after and not is_stored(prefix .. name) then
+                table.insert(children, { name, prefix .. name, false, true })
+            end
+            start, iterator = prefix .. name .. '0', 'GE'
+        end
+    end
+    return children
+end
+
+--- Error codes raised by kv_incr, mirrored in the repository.
+local ERR_VALUE_ENCODED = 10002
+local ERR_NOT_A_NUMBER = 10003
+local ERR_OUT_OF_BOUNDS = 10004
+
+local function is_number(v)
+    return type(v) == 'number' or ffi.istype('int64_t', v) or ffi.istype('uint64_t', v)
+end
+
+--- Builds the update path of a field inside the value, e.g. value["a"]["b"].
+local function value_path(path, depth)
+    local parts = { 'value' }
+    for i = 1, depth do
+        table.insert(parts, '["' .. path[i] .. '"]')
+    end
+    return table.concat(parts)
+end
+
+--- Adds delta to the number at path inside a plain value and returns the
+--- updated tuple. Missing keys and paths start from zero. Bounds are
+--- inclusive and checked against the result before anything is written.
+function kv_incr(key, path, delta, min, max)
+    return box.atomic(function()
+        local space = box.space.kv_storage
+        local current = space:get({ key })
+        if current ~= nil and (current.codec ~= nil or current.kid ~= nil) then
+            box.error({ code = ERR_VALUE_ENCODED, reason = 'value is stored encoded' })
+        end
+
+        -- Walk down to the counter, remembering how deep the value goes.
+        local node, depth = current ~= nil and current.value or {}, 0
+        for _, segment in ipairs(path) do
+            if type(node) ~= 'table' then
+                box.error({ code = ERR_NOT_A_NUMBER, reason = 'value at path is not a number' })
+            end
+            if node[segment] == nil then
+                break
+            end
+            node, depth = node[segment], depth + 1
+        end
+
+        local exists = depth == #path
+        if exists and not is_number(node) then
+            box.error({ code = ERR_NOT_A_NUMBER, reason = 'value at path is not a number' })
+        end
+
+        local result = (exists and node or 0) + delta
+        if (min ~= nil and result < min) or (max ~= nil and result > max) then
+            box.error({ code = ERR_OUT_OF_BOUNDS, reason = 'counter would leave its bounds' })
+        end
+
+        if exists then
+            local op, amount = '+', delta
+            if delta < 0 then
+                op, amount = '-', -delta
+            end
+            return space:update({ key }, { { op, value_path(path, #path), amount } })
+        end
+
+        -- Create the missing part of the path holding the initial value.
+        local subtree = result
+        for i = #path, depth + 2, -1 do
+            subtree = { [path[i]] = subtree }
+        end
+        if current == nil then
+            return space:insert({ key, { [path[1]] = subtree } })
+        end
+        return space:update({ key }, { { '=', value_path(path, depth + 1), subtree } })
+    end)
+end
+
+--- Error codes raised by the lock functions, mirrored in the repository.
+local ERR_LOCK_HELD = 10005
+local ERR_LOCK_NOT_HELD = 10006
+
+--- Returns the lock unless its lease has expired.
+local function live_lock(name)
+    local lock = box.space.kv_locks:get({ name })
+    if lock ~= nil and lock.expires_at <= now_ms() then
+        return nil
+    end
+    return lock
+end
+
+--- Acquires the lock for owner with a new fencing token. Acquiring a lock
+--- the owner already holds extends its lease and keeps the token.
+function kv_lock_acquire(name, owner, ttl_ms)
+    return box.atomic(function()
+        local lock = live_lock(name)
+        if lock ~= nil and lock.owner ~= owner then
+            box.error({ code = ERR_LOCK_HELD, reason = 'lock is held by another owner' })
+        end
+        local token = lock ~= nil and lock.token or box.sequence.kv_lock_tokens:next()
+        return box.space.kv_locks:replace({ name, owner, token, now_ms() + ttl_ms })
+    end)
+end
+
+--- Extends the lease of a lock still held by owner with the given token.
+function kv_lock_renew(name, owner, token, ttl_ms)
+    return box.atomic(function()
+        local lock = live_lock(name)
+        if lock == nil or lock.owner ~= owner or lock.token ~= token then
+            box.error({ code = ERR_LOCK_NOT_HELD, reason = 'lock is not held' })
+        end
+        return box.space.kv_locks:update({ name }, { { '=', 'expires_at', now_ms() + ttl_ms } })
+    end)
+end
+
+--- Releases a lock still held by owner with the given token.
+function kv_lock_release(name, owner, token)
+    return box.atomic(function()
+        local lock = live_lock(name)
+        if lock == nil or lock.owner ~= owner or lock.token ~= token then
+            box.error({ code = ERR_LOCK_NOT_HELD, reason = 'lock is not held' })
+        end
+        return box.space.kv_locks:delete({ name })
+    end)
+end
+
+--- Removes expired leases. Expired locks are already free for the
+--- functions above, this only keeps the space small.
+fiber.create(function()
+    fiber.name('kv_lock_expiry')
+    while true do
+        fiber.sleep(1)
+        if not box.info.ro and box.space.kv_locks ~= nil then
+            local expired = {}
+            for _, lock in box.space.kv_locks.index.expires_at:pairs({ now_ms() }, { iterator = 'LE' }) do
+                table.insert(expired, lock.name)
+            end
+            for _, name in ipairs(expired) do
+                box.space.kv_locks:delete({ name })
+            end
+        end
+    end
+end)
+
+--- Error codes raised by the queue functions, mirrored in the repository.
+local ERR_TASK_NOT_FOUND = 10007
+local ERR_TASK_NOT_TAKEN = 10008
+local ERR_TASK_RECEIPT = 10016
+
+--- Wakes up long-polling takes of a queue.
+local queue_conds = {}
+
+local function queue_cond(queue)
+    if queue_conds[queue] == nil then
+        queue_conds[queue] = fiber.cond()
+    end
+    return queue_conds[queue]
+end
+
+--- Returns the first task of the queue with the status due by now.
+local function due_task(queue, status, now)
+    for _, task in box.space.kv_queue_tasks.index.queue_status:pairs({ queue, status }, { iterator = 'EQ' }) do
+        if task.ready_at > now then
+            return nil
+        end
+        return task
+    end
+    return nil
+end
+
+local function task_of(queue, id)
+    local task = box.space.kv_queue_tasks:get({ id })
+    if task == nil or task.queue ~= queue then
+        box.error({ code = ERR_TASK_NOT_FOUND, reason = 'task not found' })
+    end
+    return task
+end
+
+--- Returns the task taken with the receipt, the attempt number returned by
+--- the take. A consumer whose visibility timeout expired gets an error once
+--- the task was taken again.
+local function taken_task(queue, id, receipt)
+    local task = task_of(queue, id)
+    if task.status ~= 'taken' then
+        box.error({ code = ERR_TASK_NOT_TAKEN, reason = 'task is not taken' })
+    end
+    if task.attempts ~= receipt then
+        box.error({ code = ERR_TASK_RECEIPT, reason = 'task was taken again' })
+    end
+    return task
+end
+
+--- Adds a task that becomes visible after delay_ms.
+function kv_queue_put(queue, payload, delay_ms)
+    local now = now_ms()
+    local task = box.space.kv_queue_tasks:insert({ box.NULL, queue, 'ready', now + delay_ms, 0, now, payload })
+    queue_cond(queue):broadcast()
+    return task
+end
+
+--- Takes the next due task, hiding it for visibility_ms. Tasks whose
+--- visibility timeout expired are redelivered first, or buried once they
+--- were taken max_attempts times.
+local function queue_take(queue, visibility_ms, max_attempts)
+    return box.atomic(function()
+        local space = box.space.kv_queue_tasks
+        local now = now_ms()
+
+        local task = due_task(queue, 'taken', now)
+        while task ~= nil and task.attempts >= max_attempts do
+            space:update({ task.id }, { { '=', 'status', 'buried' } })
+            task = due_task(queue, 'taken', now)
+        end
+        if task == nil then
+            task = due_task(queue, 'ready', now)
+        end
+        if task == nil then
+            return nil
+        end
+
+        return space:update({ task.id }, {
+            { '=', 'status', 'taken' },
+            { '=', 'ready_at', now + visibility_ms },
+            { '+', 'attempts', 1 },
+        })
+    end)
+end
+
+--- Takes the next due task, waiting up to wait_ms for one to appear.
+function kv_queue_take(queue, visibility_ms, max_attempts, wait_ms)
+    local deadline = fiber.clock() + wait_ms / 1000
+    while true do
+        local task = queue_take(queue, visibility_ms, max_attempts)
+        if task ~= nil then
+            return task
+        end
+        local remaining = deadline - fiber.clock()
+        if remaining <= 0 then
+            return nil
+        end
+        -- Delayed tasks and expired visibility timeouts do not signal.
+        queue_cond(queue):wait(math.min(remaining, 0.1))
+    end
+end
+
+--- Removes a taken task once it was processed.
+function kv_queue_ack(queue, id, receipt)
+    return box.atomic(function()
+        taken_task(queue, id, receipt)
+        return box.space.kv_queue_tasks:delete({ id })
+    end)
+end
+
+--- Returns a taken task to the queue after delay_ms, or buries it once it
+--- was taken max_attempts times.
+function kv_queue_nack(queue, id, receipt, delay_ms, max_attempts)
+    local task = box.atomic(function()
+        local task = taken_task(queue, id, receipt)
+        if task.attempts >= max_attempts then
+            return box.space.kv_queue_tasks:update({ id }, { { '=', 'status', 'buried' } })
+        end
+        return box.space.kv_queue_tasks:update({ id }, {
+            { '=', 'status', 'ready' },
+            { '=', 'ready_at', now_ms() + delay_ms },
+        })
+    end)
+    queue_cond(queue):broadcast()
+    return task
+end
+
+--- Moves a taken task to the dead letters of its queue.
+function kv_queue_bury(queue, id, receipt)
+    return box.atomic(function()
+        taken_task(queue, id, receipt)
+        return box.space.kv_queue_tasks:update({ id }, { { '=', 'status', 'buried' } })
+    end)
+end
+
+--- Error codes raised by the audit functions, mirrored in the repository.
+local ERR_AUDIT_APPEND_ONLY = 10009
+local ERR_NOT_AUDITED = 10010
+
+local function value_hash(tuple)
+    if tuple == nil then
+        return nil
+    end
+    return digest.sha256_hex(msgpack.encode(tuple.value))
+end
+
+--- Returns the hash of the current value of the key recorded by its last
+--- audit entry, nil if there is none.
+local function recorded_hash(key)
+    for _, entry in box.space.kv_audit.index.key:pairs({ key }, { iterator = 'REQ' }) do
+        if entry.op:sub(1, 5) ~= 'blob_' then
+            return entry.new_hash
+        end
+    end
+    return nil
+end
+
+--- Records a change of a stored value in `kv_audit`, in the transaction of
+--- the change. Changes made outside of kv_audited, e.g. by background jobs
+--- or from the console, are attributed to 'system'. A value written by
+--- kv_audited is hashed in its plain form, passed as the value_hash of the
+--- actor, others in their stored form.
+local function audit_change(old, new)
+    local storage = fiber.self().storage
+    if box.space.kv_audit == nil or storage.kv_unrecorded or storage.kv_unchanged then
+        return
+    end
+    if value_hash(old) == value_hash(new) then
+        -- Only the indexed projection changed.
+        return
+    end
+
+    local actor = storage.kv_audit_actor or {}
+    local key, old_hash, new_hash = (new or old).key, nil, nil
+    if old ~= nil then
+        old_hash = recorded_hash(key) or value_hash(old)
+    end
+    if new ~= nil then
+        new_hash = actor.value_hash or value_hash(new)
+    end
+
+    local op = 'update'
+    if old == nil then
+        op = 'create'
+    elseif new == nil then
+        op = 'delete'
+    end
+
+    box.space.kv_audit:insert({
+        box.NULL, key, op, now_ms(),
+        actor.principal or 'system', actor.client_ip or '', actor.request_id or '',
+        old_hash, new_hash,
+    })
+end
+
+--- Records a change of a blob in `kv_audit` like audit_change, with the
+--- blob operations. The hashes are those of the blob metadata, whose
+--- generation changes with every upload.
+local function audit_blob_change(old, new)
+    if box.space.kv_audit == nil then
+        return
+    end
+
+    local op = 'blob_update'
+    if old == nil then
+        op = 'blob_create'
+    elseif new == nil then
+        op = 'blob_delete'
+    end
+
+    local actor = fiber.self().storage.kv_audit_actor or {}
+    box.space.kv_audit:insert({
+        box.NULL, (new or old).key, op, now_ms(),
+        actor.principal or 'system', actor.client_ip or '', actor.request_id or '',
+        old ~= nil and digest.sha256_hex(msgpack.encode(old)) or box.NULL,
+        new ~= nil and digest.sha256_hex(msgpack.encode(new)) or box.NULL,
+    })
+end
+
+local function append_only(old)
+    if old ~= nil then
+        box.error({ code = ERR_AUDIT_APPEND_ONLY, reason = 'audit trail is append-only' })
+    end
+end
+
+--- Error codes raised by the history functions, mirrored in the repository.
+local ERR_VERSION_NOT_FOUND = 10011
+local ERR_VERSION_DELETED = 10012
+
+--- Maps missing fields of tuples written before a field was added to null.
+local function nullable(v)
+    if v == nil then
+        return box.NULL
+    end
+    return v
+end
+
+local function version_row(key, version, at, expires_at, tuple)
+    if tuple == nil then
+        return { key, version, at, expires_at, box.NULL, box.NULL, box.NULL, box.NULL }
+    end
+    return { key, version, at, expires_at, tuple.value, nullable(tuple.codec), nullable(tuple.kid), nullable(tuple.attrs) }
+end
+
+--- Records a change of a stored value as a new version in `kv_history`,
+--- following the policy passed to kv_audited: at most `versions` versions
+--- per key, a replaced version expiring after `max_age_ms`. Changes made
+--- outside of kv_audited, e.g. re-encryption, and writes of the same plain
+--- value add no version.
+local function record_version(old, new)
+    local storage = fiber.self().storage
+    local policy = storage.kv_history_policy
+    if policy == nil or box.space.kv_history == nil or storage.kv_unrecorded or storage.kv_unchanged
+        or value_hash(old) == value_hash(new) then
+        return
+    end
+
+    local history, key, now = box.space.kv_history, (new or old).key, now_ms()
+    local expires_at = now + policy.max_age_ms
+
+    local last = history.index.primary:max({ key })
+    if last == nil and old ~= nil then
+        -- The value predates its history, keep it as the first version.
+        last = history:insert(version_row(key, 1, now, expires_at, old))
+    elseif last ~= nil and last.expires_at == nil then
+        history:update({ key, last.version }, { { '=', 'expires_at', expires_at } })
+    end
+
+    local version = last ~= nil and last.version + 1 or 1
+    if new ~= nil then
+        history:insert(version_row(key, version, now, box.NULL, new))
+    else
+        history:insert(version_row(key, version, now, expires_at, nil))
+    end
+
+    if version <= policy.versions then
+        return
+    end
+    local stale = {}
+    for _, row in history.index.primary:pairs({ key, version - policy.versions }, { iterator = 'LE' }) do
+        if row.key ~= key then
+            break
+        end
+        table.insert(stale, row.version)
+    end
+    for _, stale_version in ipairs(stale) do
+        history:delete({ key, stale_version })
+    end
+end
+
+--- Reinstates a version of the key as its current value with attrs, its
+--- projection on the indexes declared now, which records it as the newest
+--- version, and returns the newest version.
+local function restore_version(key, version, attrs)
+    return box.atomic(function()
+        local row = box.space.kv_history ~= nil and box.space.kv_history:get({ key, version }) or nil
+        if row == nil then
+            box.error({ code = ERR_VERSION_NOT_FOUND, reason = 'version not found' })
+        end
+        if row.value == nil then
+            box.error({ code = ERR_VERSION_DELETED, reason = 'key is deleted in this version' })
+        end
+        box.space.kv_storage:replace({ key, row.value, row.codec, row.kid, nullable(attrs) })
+        return box.space.kv_history.index.primary:max({ key })
+    end)
+end
+
+--- Rows deleted per transaction by expire.
+local EXPIRY_BATCH = 1000
+
+--- Starts a fiber deleting the rows of the space whose time in the index,
+--- on an expires_at field, is past, a batch per transaction. It waits 10
+--- seconds after a pass that found less than a batch.
+local function expire(space_name, index_name)
+    fiber.create(function()
+        fiber.name(space_name .. '_expiry')
+        while true do
+            local space, expired = box.space[space_name], {}
+            if not box.info.ro and space ~= nil then
+                expired = space.index[index_name]:select({ now_ms() }, { iterator = 'LE', limit = EXPIRY_BATCH })
+                local primary = key_def.new(space.index.primary.parts)
+                box.atomic(function()
+                    for _, row in ipairs(expired) do
+                        space:delete(primary:extract_key(row))
+                    end
+                end)
+            end
+            if #expired < EXPIRY_BATCH then
+                fiber.sleep(10)
+            end
+        end
+    end)
+end
+
+--- Removes versions replaced longer than their maximum age ago.
+expire('kv_history', 'expires_at')
+
+--- Error codes raised by the trash functions, mirrored in the repository.
+local ERR_NOT_IN_TRASH = 10013
+
+--- Moves the key to `kv_trash` for retention_ms along with who deleted it,
+--- and returns the deleted tuple.
+local function soft_delete(key, retention_ms)
+    return box.atomic(function()
+        local tuple = box.space.kv_storage:delete({ key })
+        if tuple == nil then
+            return nil
+        end
+        local actor, now = fiber.self().storage.kv_audit_actor or {}, now_ms()
+        box.space.kv_trash:replace({
+            key, tuple.value, nullable(tuple.codec), nullable(tuple.kid), nullable(tuple.attrs),
+            now, now + retention_ms, actor.principal or 'system', actor.request_id or '',
+        })
+        return tuple
+    end)
+end
+
+--- Moves the key back from `kv_trash` unless it expired or was created
+--- again in the meantime, and returns the restored tuple.
+local function undelete(key)
+    return box.atomic(function()
+        local trashed = box.space.kv_trash ~= nil and box.space.kv_trash:get({ key }) or nil
+        if trashed == nil or trashed.expires_at <= now_ms() then
+            box.error({ code = ERR_NOT_IN_TRASH, reason = 'key is not in the trash' })
+        end
+        box.space.kv_trash:delete({ key })
+        return box.space.kv_storage:insert({ key, trashed.value, trashed.codec, trashed.kid, trashed.attrs })
+    end)
+end
+
+--- Purges keys kept in the trash for longer than their retention.
+expire('kv_trash', 'expires_at')
+
+--- Installs a trigger of the space, on_replace or before_replace, unless
+--- it is already installed.
+local function install_trigger(space, kind, trigger)
+    for _, installed in ipairs(space[kind](space)) do
+        if installed == trigger then
+            return
+        end
+    end
+    space[kind](space, trigger)
+end
+
+--- Installs the triggers of the audit trail and the history once their
+--- spaces exist, each on its own so that a trigger missing for any reason
+--- is installed again.
+local function install_triggers()
+    if box.space.kv_audit ~= nil then
+        install_trigger(box.space.kv_audit, 'before_replace', append_only)
+        install_trigger(box.space.kv_storage, 'on_replace', audit_change)
+        if box.space.kv_blobs ~= nil then
+            install_trigger(box.space.kv_blobs, 'on_replace', audit_blob_change)
+        end
+    end
+    if box.space.kv_history ~= nil then
+        install_trigger(box.space.kv_storage, 'on_replace', record_version)
+    end
+end
+
+--- Changes of `kv_storage` run by kv_audited.
+local audited_ops = {
+    insert = function(tuple) return box.space.kv_storage:insert(tuple) end,
+    replace = function(tuple) return box.space.kv_storage:replace(tuple) end,
+    update = function(key, ops) return box.space.kv_storage:update(key, ops) end,
+    delete = function(key) return box.space.kv_storage:delete(key) end,
+    restore = restore_version,
+    soft_delete = soft_delete,
+    undelete = undelete,
+    kv_incr = function(...) return kv_incr(...) end,
+    kv_swap_value = function(...) return kv_swap_value(...) end,
+    kv_blob_commit = kv_blob_commit,
+    kv_blob_delete = kv_blob_delete,
+}
+
+--- Operations of audited_ops changing `kv_blobs`, which leave the value of
+--- the key in its tier.
+local blob_ops = { kv_blob_commit = true, kv_blob_delete = true }
+
+--- Runs op, a request of `kv_storage` or a function changing it or a blob,
+--- on behalf of actor, a map of the principal, client_ip and request_id
+--- recorded with the change, and the value_hash of the plain value written
+--- if any. Writing the plain value the key already holds is neither
+--- audited nor versioned. history is the policy of the versions of the
+--- key, a map of versions and max_age_ms, or nil to keep no version.
+--- Returns nothing instead of nil, e.g. for a missing key. It runs with the
+--- privileges of the admin user, the only one writing `kv_audit`.
+function kv_audited(actor, history, op, ...)
+    local fn = audited_ops[op]
+    if fn == nil then
+        box.error({ code = ERR_NOT_AUDITED, reason = 'operation ' .. tostring(op) .. ' is not audited' })
+    end
+
+    -- The key is the first argument, alone or first in a tuple or key.
+    local first = ...
+    local key = type(first) == 'table' and first[1] or first
+    local result = with_latch(key, function(...)
+        if not blob_ops[op] then
+            promote(key)
+        end
+
+        local storage = fiber.self().storage
+        storage.kv_audit_actor, storage.kv_history_policy = actor, history
+        storage.kv_unchanged = actor.value_hash ~= nil and box.space.kv_audit ~= nil
+            and box.space.kv_storage:get({ key }) ~= nil and recorded_hash(key) == actor.value_hash
+        local ok, result = pcall(fn, ...)
+        storage.kv_audit_actor, storage.kv_history_policy, storage.kv_unchanged = nil, nil, nil
+        if not ok then
+            error(result)
+        end
+
+        if box.space.kv_storage:get({ key }) == nil then
+            forget(key)
+        elseif tiering then
+            touch(key, now_ms())
+        end
+        return result
+    end, ...)
+    if result == nil then
+        return
+    end
+    return result
+end
+
+--- Returns up to limit audit entries following the entry after_id, oldest
+--- first: of the key unless it is nil, made in [since_ms, until_ms) with
+--- either bound open when nil.
+function kv_audit_query(key, since_ms, until_ms, after_id, limit)
+    local space = box.space.kv_audit
+    local entries = {}
+
+    local iter
+    if key ~= nil then
+        iter = space.index.key:pairs({ key, after_id }, { iterator = 'GT' })
+    else
+        local start = after_id
+        if since_ms ~= nil then
+            local first = space.index.at:select({ since_ms }, { iterator = 'GE', limit = 1 })[1]
+            if first == nil then
+                return entries
+            end
+            start = math.max(start, first.id - 1)
+        end
+        iter = space.index.primary:pairs({ start }, { iterator = 'GT' })
+    end
+
+    for _, entry in iter do
+        if key ~= nil and entry.key ~= key then
+            break
+        end
+        if until_ms ~= nil and entry.at >= until_ms then
+            break
+        end
+        if since_ms == nil or entry.at >= since_ms then
+            table.insert(entries, entry)
+            if #entries >= limit then
+                break
+            end
+        end
+    end
+    return entries
+end
+
+--- Error codes raised by the collection functions, mirrored in the
+--- repository.
+local ERR_COLLECTION_EXISTS = 10014
+local ERR_COLLECTION_NOT_FOUND = 10015
+
+--- Creates a collection defined over HTTP and registers it in
+--- `kv_collections`. A collection whose indexes cannot be created is
+--- dropped again.
+function kv_collection_create(name, engine, key, indexes)
+    if box.space[name] ~= nil then
+        box.error({ code = ERR_COLLECTION_EXISTS, reason = 'collection already exists' })
+    end
+
+    local ok, err = pcall(create_collection, name, engine, key, indexes)
+    if not ok then
+        if box.space[name] ~= nil then
+            box.space[name]:drop()
+        end
+        error(err)
+    end
+    return box.space.kv_collections:insert({ name, engine, key, indexes, now_ms() })
+end
+
+--- Drops a collection defined over HTTP together with its items.
+function kv_collection_drop(name)
+    local collection = box.space.kv_collections:get({ name })
+    if collection == nil then
+        box.error({ code = ERR_COLLECTION_NOT_FOUND, reason = 'collection not found' })
+    end
+    if box.space[name] ~= nil then
+        box.space[name]:drop()
+    end
+    box.space.kv_collections:delete({ name })
+    return collection
+end
+
+--- Applied migrations, keyed by version.
+box.schema.space.create('_migrations', {
+    if_not_exists = true,
+    format = {
+        { name = 'version', type = 'unsigned' },
+        { name = 'name', type = 'str' },
+        { name = 'applied_at', type = 'unsigned' }, -- seconds since the epoch
+    },
+})
+box.space._migrations:create_index('primary', { parts = { 'version' }, if_not_exists = true })
+
+-- Steps applied by box.once before migrations were introduced.
+for _, migration in ipairs(migrations) do
+    if migration.once ~= nil and box.space._schema:get({ 'once' .. migration.once }) ~= nil
+        and box.space._migrations:get({ migration.version }) == nil then
+        box.space._migrations:insert({ migration.version, migration.name, os.time() })
+    end
+end
+
+local function migration_row(migration)
+    local applied = box.space._migrations:get({ migration.version })
+    return { migration.version, migration.name, applied ~= nil and applied.applied_at or 0 }
+end
+
+--- Lists every known migration with the time it was applied, 0 if pending.
+function kv_migrations_status()
+    local rows = {}
+    for _, migration in ipairs(migrations) do
+        table.insert(rows, migration_row(migration))
+    end
+    return rows
+end
+
+--- Applies pending migrations up to target, every one when target is nil,
+--- and returns the applied ones.
+function kv_migrate_up(target)
+    local rows = {}
+    for _, migration in ipairs(migrations) do
+        if target ~= nil and migration.version > target then
+            break
+        end
+        if box.space._migrations:get({ migration.version }) == nil then
+            migration.up()
+            box.space._migrations:insert({ migration.version, migration.name, os.time() })
+            table.insert(rows, migration_row(migration))
+        end
+    end
+    create_declared_indexes()
+    install_triggers()
+    return rows
+end
+
+--- Reverts applied migrations newer than target, newest first, and returns
+--- the reverted ones.
+function kv_migrate_down(target)
+    local rows = {}
+    for i = #migrations, 1, -1 do
+        local migration = migrations[i]
+        if migration.version <= target then
+            break
+        end
+        if box.space._migrations:get({ migration.version }) ~= nil then
+            migration.down()
+            box.space._migrations:delete({ migration.version })
+            if migration.once ~= nil then
+                box.space._schema:delete({ 'once' .. migration.once })
+            end
+            table.insert(rows, migration_row(migration))
+        end
+    end
+    return rows
+end
+
+-- Migration and collection functions, and kv_audited whose triggers write
+-- `kv_audit`, run with the privileges of the admin user.
+for _, name in ipairs({
+    'kv_migrations_status', 'kv_migrate_up', 'kv_migrate_down', 'kv_collection_create', 'kv_collection_drop',
+    'kv_audited',
+}) do
+    box.schema.func.create(name, { setuid = true, if_not_exists = true })
+end
+
+create_declared_indexes()
+create_declared_collections()
+install_triggers()
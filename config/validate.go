@@ -0,0 +1,235 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values accepted by Validate, kept in sync with the packages using them.
+var (
+	environments          = []string{"local", "prod", "production"}
+	logLevels             = []string{"", "debug", "info", "warn", "error"}
+	logOutputs            = []string{LogOutputStdout, LogOutputStderr, LogOutputFile}
+	compressionAlgorithms = []string{"none", "zstd", "snappy"}
+	indexTypes            = []string{"string", "unsigned", "integer", "number", "boolean"}
+	keyPartTypes          = []string{"string", "unsigned", "integer", "uuid"}
+)
+
+// collectionName is the name of a collection space, "kv" and "kv_" being
+// reserved for the application.
+var collectionName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// problems collects every violation found by Validate.
+type problems []error
+
+func (p *problems) add(path, format string, args ...any) {
+	*p = append(*p, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (p *problems) oneOf(path, value string, allowed []string) {
+	for _, v := range allowed {
+		if v == value {
+			return
+		}
+	}
+	p.add(path, "%q is not one of %q", value, allowed)
+}
+
+func (p *problems) port(path, value string) {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		p.add(path, "%q is not a port number", value)
+	}
+}
+
+func (p *problems) positive(path string, value time.Duration) {
+	if value <= 0 {
+		p.add(path, "must be positive, got %s", value)
+	}
+}
+
+// Validate checks the settings against each other and their allowed
+// values, reporting every problem at once.
+func (c Config) Validate() error {
+	var p problems
+
+	p.oneOf("app.environment", c.App.Environment, environments)
+	p.oneOf("app.log_level", c.App.LogLevel, logLevels)
+	if c.App.ReloadInterval < 0 {
+		p.add("app.reload_interval", "must not be negative, got %s", c.App.ReloadInterval)
+	}
+
+	p.oneOf("log.output", c.Log.Output, logOutputs)
+	if c.Log.Output == LogOutputFile {
+		if c.Log.File == "" {
+			p.add("log.file", "must be set when logging to a file")
+		}
+		if c.Log.MaxSizeMB <= 0 {
+			p.add("log.max_size_mb", "must be positive, got %d", c.Log.MaxSizeMB)
+		}
+		if c.Log.MaxBackups < 0 {
+			p.add("log.max_backups", "must not be negative, got %d", c.Log.MaxBackups)
+		}
+		if c.Log.MaxAge < 24*time.Hour {
+			p.add("log.max_age", "must be at least a day, got %s", c.Log.MaxAge)
+		}
+	}
+
+	if c.Redaction.MaxValueLength <= 0 {
+		p.add("redaction.max_value_length", "must be positive, got %d", c.Redaction.MaxValueLength)
+	}
+
+	p.port("http_server.port", c.HTTPServer.Port)
+	if c.Storage.Host == "" {
+		p.add("storage.host", "must be set")
+	}
+	p.port("storage.port", c.Storage.Port)
+
+	if c.Blob.ChunkSize <= 0 {
+		p.add("blob.chunk_size", "must be positive, got %d", c.Blob.ChunkSize)
+	}
+	if c.Blob.MaxSize < int64(c.Blob.ChunkSize) {
+		p.add("blob.max_size", "must be at least chunk_size, got %d", c.Blob.MaxSize)
+	}
+	p.positive("blob.upload_timeout", c.Blob.UploadTimeout)
+	p.positive("blob.retention", c.Blob.Retention)
+
+	p.oneOf("compression.algorithm", c.Compression.Algorithm, compressionAlgorithms)
+	if c.Compression.Threshold < 0 {
+		p.add("compression.threshold", "must not be negative, got %d", c.Compression.Threshold)
+	}
+	for i, rule := range c.Compression.Rules {
+		if rule.Prefix == "" {
+			p.add(fmt.Sprintf("compression.rules[%d].prefix", i), "must be set")
+		}
+		p.oneOf(fmt.Sprintf("compression.rules[%d].algorithm", i), rule.Algorithm, compressionAlgorithms)
+	}
+
+	if c.History.Versions <= 0 {
+		p.add("history.versions", "must be positive, got %d", c.History.Versions)
+	}
+	p.positive("history.max_age", c.History.MaxAge)
+	for i, rule := range c.History.Rules {
+		if rule.Prefix == "" {
+			p.add(fmt.Sprintf("history.rules[%d].prefix", i), "must be set")
+		}
+		if rule.Versions < 0 {
+			p.add(fmt.Sprintf("history.rules[%d].versions", i), "must not be negative, got %d", rule.Versions)
+		}
+		if rule.MaxAge < 0 {
+			p.add(fmt.Sprintf("history.rules[%d].max_age", i), "must not be negative, got %s", rule.MaxAge)
+		}
+	}
+
+	p.positive("trash.retention", c.Trash.Retention)
+
+	p.positive("tiering.idle_after", c.Tiering.IdleAfter)
+	p.positive("tiering.interval", c.Tiering.Interval)
+	if c.Tiering.Batch <= 0 {
+		p.add("tiering.batch", "must be positive, got %d", c.Tiering.Batch)
+	}
+	// The indexes are on kv_storage, a query would miss the demoted keys.
+	if c.Tiering.Enabled && len(c.Indexes) > 0 {
+		p.add("tiering.enabled", "must not be set while indexes are declared, they cover the keys in memory only")
+	}
+
+	if c.Encryption.Enabled && c.Encryption.KeyringFile == "" {
+		p.add("encryption.keyring_file", "must be set when encryption is enabled")
+	}
+	p.positive("encryption.rotation_interval", c.Encryption.RotationInterval)
+	if c.Encryption.RotationBatch <= 0 {
+		p.add("encryption.rotation_batch", "must be positive, got %d", c.Encryption.RotationBatch)
+	}
+
+	for i, schema := range c.Validation.Schemas {
+		if schema.Prefix == "" {
+			p.add(fmt.Sprintf("validation.schemas[%d].prefix", i), "must be set")
+		}
+		if schema.File == "" {
+			p.add(fmt.Sprintf("validation.schemas[%d].file", i), "must be set")
+		}
+	}
+	p.positive("validation.refresh_interval", c.Validation.RefreshInterval)
+
+	names := make(map[string]bool, len(c.Indexes))
+	for i, index := range c.Indexes {
+		path := fmt.Sprintf("indexes[%d]", i)
+		switch {
+		case index.Name == "":
+			p.add(path+".name", "must be set")
+		case names[index.Name]:
+			p.add(path+".name", "%q is declared twice", index.Name)
+		}
+		names[index.Name] = true
+		if index.Path == "" {
+			p.add(path+".path", "must be set")
+		}
+		p.oneOf(path+".type", index.Type, indexTypes)
+	}
+
+	collections := make(map[string]bool, len(c.Collections))
+	for i, collection := range c.Collections {
+		path := fmt.Sprintf("collections[%d]", i)
+		switch {
+		case !collectionName.MatchString(collection.Name) || collection.Name == "kv" || strings.HasPrefix(collection.Name, "kv_"):
+			p.add(path+".name", "must be lowercase letters, digits and underscores, not kv nor starting with kv_, got %q", collection.Name)
+		case collections[collection.Name]:
+			p.add(path+".name", "%q is declared twice", collection.Name)
+		}
+		collections[collection.Name] = true
+
+		if len(collection.Key) == 0 {
+			p.add(path+".key", "must have at least one part")
+		}
+		fields := make(map[string]bool, len(collection.Key))
+		for j, part := range collection.Key {
+			partPath := fmt.Sprintf("%s.key[%d]", path, j)
+			switch {
+			case part.Field == "" || part.Field == "value":
+				p.add(partPath+".field", "must be set and not be value")
+			case fields[part.Field]:
+				p.add(partPath+".field", "%q is declared twice", part.Field)
+			}
+			fields[part.Field] = true
+			p.oneOf(partPath+".type", part.Type, keyPartTypes)
+		}
+	}
+
+	if c.SQL.MaxRows <= 0 {
+		p.add("sql.max_rows", "must be positive, got %d", c.SQL.MaxRows)
+	}
+	p.positive("sql.timeout", c.SQL.Timeout)
+	if c.SQL.MaxSteps == 0 {
+		p.add("sql.max_steps", "must be positive, got %d", c.SQL.MaxSteps)
+	}
+
+	p.positive("locks.default_ttl", c.Locks.DefaultTTL)
+	if c.Locks.MaxTTL < c.Locks.DefaultTTL {
+		p.add("locks.max_ttl", "must be at least default_ttl, got %s", c.Locks.MaxTTL)
+	}
+	p.positive("locks.leader_ttl", c.Locks.LeaderTTL)
+
+	p.positive("queues.visibility", c.Queues.Visibility)
+	if c.Queues.MaxVisibility < c.Queues.Visibility {
+		p.add("queues.max_visibility", "must be at least visibility, got %s", c.Queues.MaxVisibility)
+	}
+	if c.Queues.MaxAttempts <= 0 {
+		p.add("queues.max_attempts", "must be positive, got %d", c.Queues.MaxAttempts)
+	}
+	if c.Queues.MaxWait < 0 {
+		p.add("queues.max_wait", "must not be negative, got %s", c.Queues.MaxWait)
+	}
+
+	p.positive("migrations.timeout", c.Migrations.Timeout)
+	if c.Migrations.AutoApply && c.Migrations.Credentials.Username == "" {
+		p.add("migrations.credentials.username", "is required with migrations.auto_apply")
+	}
+
+	if len(p) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n%w", errors.Join(p...))
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig reads the shipped app_config.yaml, which must be valid.
+func validConfig(t *testing.T) Config {
+	t.Helper()
+	t.Setenv("TT_HOST", "localhost")
+	t.Setenv("TT_PORT", "3301")
+	t.Setenv("TT_USER", "user")
+	t.Setenv("TT_PASSWORD", "password")
+
+	cfg, err := Read("app_config.yaml")
+	if err != nil {
+		t.Fatalf("read app_config.yaml: %v", err)
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string // paths reported, none for a valid config
+	}{
+		{"shipped config", func(*Config) {}, nil},
+		{"unknown environment", func(c *Config) { c.App.Environment = "staging" }, []string{"app.environment"}},
+		{"production alias", func(c *Config) { c.App.Environment = "production" }, nil},
+		{"negative reload interval", func(c *Config) { c.App.ReloadInterval = -time.Second }, []string{"app.reload_interval"}},
+		{"file output without file", func(c *Config) {
+			c.Log.Output = LogOutputFile
+			c.Log.File = ""
+			c.Log.MaxAge = time.Hour
+		}, []string{"log.file", "log.max_age"}},
+		{"invalid ports", func(c *Config) {
+			c.HTTPServer.Port = "http"
+			c.Storage.Port = "70000"
+		}, []string{"http_server.port", "storage.port"}},
+		{"blob smaller than a chunk", func(c *Config) { c.Blob.MaxSize = int64(c.Blob.ChunkSize) - 1 }, []string{"blob.max_size"}},
+		{"zero blob retention", func(c *Config) { c.Blob.Retention = 0 }, []string{"blob.retention"}},
+		{"compression rule", func(c *Config) {
+			c.Compression.Rules = []CompressionRule{{Prefix: "", Algorithm: "gzip"}}
+		}, []string{"compression.rules[0].prefix", "compression.rules[0].algorithm"}},
+		{"history rule", func(c *Config) {
+			c.History.Rules = []HistoryRule{{Prefix: "audit/", Versions: -1, MaxAge: -time.Hour}}
+		}, []string{"history.rules[0].versions", "history.rules[0].max_age"}},
+		{"encryption without keyring", func(c *Config) {
+			c.Encryption.Enabled = true
+			c.Encryption.KeyringFile = ""
+		}, []string{"encryption.keyring_file"}},
+		{"duplicate index", func(c *Config) {
+			c.Indexes = []IndexConfig{
+				{Name: "by_email", Path: "email", Type: "string"},
+				{Name: "by_email", Path: "email", Type: "text"},
+			}
+		}, []string{"indexes[1].name", "indexes[1].type"}},
+		{"reserved collection name", func(c *Config) {
+			c.Collections = []CollectionConfig{{Name: "kv_users", Key: []KeyPartConfig{{Field: "id", Type: "unsigned"}}}}
+		}, []string{"collections[0].name"}},
+		{"default collection name", func(c *Config) {
+			c.Collections = []CollectionConfig{{Name: "kv", Key: []KeyPartConfig{{Field: "id", Type: "unsigned"}}}}
+		}, []string{"collections[0].name"}},
+		{"collection key", func(c *Config) {
+			c.Collections = []CollectionConfig{{Name: "users", Key: []KeyPartConfig{
+				{Field: "value", Type: "string"},
+				{Field: "id", Type: "unsigned"},
+				{Field: "id", Type: "float"},
+			}}}
+		}, []string{"collections[0].key[0].field", "collections[0].key[2].field", "collections[0].key[2].type"}},
+		{"collection without key", func(c *Config) {
+			c.Collections = []CollectionConfig{{Name: "users"}}
+		}, []string{"collections[0].key"}},
+		{"lock ttls", func(c *Config) { c.Locks.MaxTTL = c.Locks.DefaultTTL - time.Second }, []string{"locks.max_ttl"}},
+		{"queue limits", func(c *Config) {
+			c.Queues.MaxAttempts = 0
+			c.Queues.MaxWait = -time.Second
+		}, []string{"queues.max_attempts", "queues.max_wait"}},
+		{"tiering with indexes", func(c *Config) {
+			c.Tiering.Enabled = true
+			c.Indexes = []IndexConfig{{Name: "by_email", Path: "email", Type: "string"}}
+		}, []string{"tiering.enabled"}},
+		{"zero sql steps", func(c *Config) { c.SQL.MaxSteps = 0 }, []string{"sql.max_steps"}},
+		{"auto apply without migrations user", func(c *Config) {
+			c.Migrations.AutoApply = true
+			c.Migrations.Credentials = MigrationCredentials{}
+		}, []string{"migrations.credentials.username"}},
+		{"auto apply with migrations user", func(c *Config) {
+			c.Migrations.AutoApply = true
+			c.Migrations.Credentials = MigrationCredentials{Username: "migrator", Password: "secret"}
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("no error, want problems with %q", tt.want)
+			}
+
+			// The first line is the "invalid config:" header.
+			lines := strings.Split(err.Error(), "\n")[1:]
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d problems, want %d:\n%v", len(lines), len(tt.want), err)
+			}
+			for i, path := range tt.want {
+				if !strings.HasPrefix(lines[i], path+": ") {
+					t.Errorf("problem %d is %q, want one with %s", i, lines[i], path)
+				}
+			}
+		})
+	}
+}
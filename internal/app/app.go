@@ -1,31 +1,119 @@
 package app
 
 import (
+	"context"
+	"os"
 	"tarantool-app/config"
 	v1 "tarantool-app/internal/infrastructure/http/v1"
 	"tarantool-app/internal/repository"
 	"tarantool-app/internal/usecases"
 	"tarantool-app/internal/utils"
+
+	"github.com/google/uuid"
 )
 
-func Run(configPath string) {
-	cfg := utils.Must(config.Load(configPath))
+// Run serves the API until the HTTP server fails. Only errors of the
+// config are returned, later failures are fatal.
+func Run(src config.Source) error {
+	cfg, err := src.Load()
+	if err != nil {
+		return err
+	}
 
-	log := NewLogger(cfg.App.Environment)
+	log, err := NewLogger(cfg)
+	if err != nil {
+		return err
+	}
 	defer log.Sync()
 
 	tt := utils.Must(repository.NewTarantoolRepository(cfg, log))
 	defer tt.Close()
 
-	usecase := usecases.NewUserUseCase(tt, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	migrations := usecases.NewMigrationUseCase(tt, repository.RequiredSchemaVersion, log)
+	if cfg.Migrations.AutoApply {
+		if err := applyMigrations(ctx, cfg, log); err != nil {
+			log.Fatal("Failed to apply migrations",
+				"error", err,
+			)
+		}
+	}
+	if err := migrations.Check(ctx); err != nil {
+		log.Fatal("Refusing to serve, run `tarantool-app migrate up`",
+			"error", err,
+		)
+	}
+
+	lockUseCase := usecases.NewLockUseCase(tt, cfg.Locks, log)
+	// Lock names starting with an underscore cannot be taken over HTTP.
+	leader := usecases.NewLeaderElection(lockUseCase, "_tarantool-app/background", instanceName(), cfg.Locks.LeaderTTL, log)
+	go leader.Run(ctx, nil)
+
+	if cfg.Encryption.Enabled {
+		go usecases.NewKeyRotation(tt, cfg.Encryption, leader, log).Run(ctx)
+	}
+	if cfg.Tiering.Enabled {
+		go usecases.NewTiering(tt, cfg.Tiering, leader, log).Run(ctx)
+	}
+
+	schemas := utils.Must(usecases.NewSchemaRegistry(tt, cfg.Validation, log))
+	go schemas.Run(ctx)
+
+	usecase := usecases.NewUserUseCase(tt, schemas, log)
+	blobUseCase := usecases.NewBlobUseCase(tt, cfg.Blob, log)
+	queryUseCase := usecases.NewQueryUseCase(tt, cfg.Indexes, log)
+	go queryUseCase.Reindex(ctx)
+
+	sqlUseCase := usecases.NewSQLUseCase(tt, cfg.SQL, cfg.Tiering, log)
+	queueUseCase := usecases.NewQueueUseCase(tt, cfg.Queues, log)
 
-	apiHandler := v1.NewRequestHandler(usecase, log)
+	go newReloader(src, cfg, log, func(old, cfg config.Config) {
+		// A level changed through the admin API stays until the config changes it.
+		if old.App.LogLevel != cfg.App.LogLevel {
+			if err := log.SetLevel(cfg.App.LogLevel); err != nil {
+				log.Warn("Failed to change log level", "error", err)
+			}
+		}
+		sqlUseCase.Reload(cfg.SQL)
+		lockUseCase.Reload(cfg.Locks)
+		queueUseCase.Reload(cfg.Queues)
+	}).Run(ctx)
 
-	r := v1.NewGinRouter(cfg.App.Environment, log, apiHandler)
+	handlers := v1.Handlers{
+		KV:         v1.NewRequestHandler(usecase, log),
+		Blob:       v1.NewBlobHandler(blobUseCase, cfg.Blob.MaxSize, log),
+		Schema:     v1.NewSchemaHandler(schemas, log),
+		Query:      v1.NewQueryHandler(queryUseCase, log),
+		SQL:        v1.NewSQLHandler(sqlUseCase, log),
+		Counter:    v1.NewCounterHandler(usecases.NewCounterUseCase(tt, schemas, log), log),
+		Lock:       v1.NewLockHandler(lockUseCase, log),
+		Queue:      v1.NewQueueHandler(queueUseCase, log),
+		Transfer:   v1.NewTransferHandler(usecases.NewTransferUseCase(tt, schemas, log), log),
+		Log:        v1.NewLogHandler(log, log),
+		Audit:      v1.NewAuditHandler(usecases.NewAuditUseCase(tt, log), log),
+		History:    v1.NewHistoryHandler(usecases.NewHistoryUseCase(tt, schemas, log), log),
+		Tree:       v1.NewTreeHandler(usecases.NewTreeUseCase(tt, log), log),
+		Collection: v1.NewCollectionHandler(usecases.NewCollectionUseCase(tt, cfg.Collections, log), log),
+	}
+
+	r := v1.NewGinRouter(cfg, log, handlers)
 
 	if err := r.Run(":" + cfg.HTTPServer.Port); err != nil {
 		log.Fatal("Failed to start HTTP server",
 			"error", err,
 		)
 	}
+	return nil
+}
+
+// instanceName identifies this process among the instances campaigning for
+// leadership.
+func instanceName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return hostname + "/" + uuid.NewString()
 }
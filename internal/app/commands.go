@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+	"tarantool-app/internal/utils"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// commandTimeout bounds the round trips of ping.
+const commandTimeout = 5 * time.Second
+
+// connect loads the config and connects to Tarantool for a one-off command.
+// The caller closes the repository and syncs the logger.
+func connect(src config.Source) (config.Config, ZapLogger, repository.Tarantool, error) {
+	return connectAs(src, func(cfg config.Config) config.Config { return cfg })
+}
+
+// connectAs is connect with the storage credentials picked by user.
+func connectAs(src config.Source, user func(config.Config) config.Config) (config.Config, ZapLogger, repository.Tarantool, error) {
+	cfg, err := src.Load()
+	if err != nil {
+		return config.Config{}, ZapLogger{}, repository.Tarantool{}, err
+	}
+
+	// Commands report to the terminal whatever the server logs to.
+	logCfg := cfg
+	logCfg.Log.Output = config.LogOutputStderr
+	log, err := NewLogger(logCfg)
+	if err != nil {
+		return config.Config{}, ZapLogger{}, repository.Tarantool{}, err
+	}
+
+	tt, err := repository.NewTarantoolRepository(user(cfg), log)
+	if err != nil {
+		log.Sync()
+		return config.Config{}, ZapLogger{}, repository.Tarantool{}, fmt.Errorf("connect to %s:%s: %w", cfg.Storage.Host, cfg.Storage.Port, err)
+	}
+	return cfg, log, tt, nil
+}
+
+// CheckConfig reads the config, environment variables and overrides
+// applied, prints it as YAML with secrets redacted and validates it.
+func CheckConfig(src config.Source, out io.Writer) error {
+	cfg, err := config.Read(src.Path, src.Overrides...)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(reflect.ValueOf(cfg.Redacted()))); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	return cfg.Validate()
+}
+
+// yamlNode builds the YAML of a config value in field order, printing
+// durations the way they are written in the config file.
+func yamlNode(v reflect.Value) *yaml.Node {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: d.String()}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+				yamlNode(v.Field(i)),
+			)
+		}
+		return node
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for i := range v.Len() {
+			node.Content = append(node.Content, yamlNode(v.Index(i)))
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v.Interface())}
+		}
+		return node
+	}
+}
+
+// Ping connects to Tarantool and prints the server version along with the
+// applied and required schema versions.
+func Ping(src config.Source, out io.Writer) error {
+	cfg, log, tt, err := connect(src)
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+	defer tt.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	server, err := tt.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	schema, err := usecases.NewMigrationUseCase(tt, repository.RequiredSchemaVersion, log).Version(ctx)
+	if err != nil {
+		return fmt.Errorf("schema version: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "address\t%s:%s\n", cfg.Storage.Host, cfg.Storage.Port)
+	fmt.Fprintf(w, "server\t%s\n", server)
+	fmt.Fprintf(w, "schema\t%d (required %d)\n", schema, repository.RequiredSchemaVersion)
+	return w.Flush()
+}
+
+// Export writes values whose key has the prefix to output, stdout when
+// output is empty or "-".
+func Export(src config.Source, prefix, format, output string) error {
+	_, log, tt, err := connect(src)
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+	defer tt.Close()
+
+	out := io.Writer(os.Stdout)
+	if output != "" && output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	transfer := usecases.NewTransferUseCase(tt, nil, log)
+	exported, err := transfer.Export(context.Background(), out, prefix, transferFormat(format, output))
+	if err != nil {
+		return err
+	}
+
+	log.Info("Export finished", "exported", exported)
+	return nil
+}
+
+// Import reads values from input, stdin when input is empty or "-", and
+// prints the import report as JSON.
+func Import(src config.Source, input string, opts domain.ImportOptions, out io.Writer) error {
+	cfg, log, tt, err := connect(src)
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+	defer tt.Close()
+
+	in := io.Reader(os.Stdin)
+	if input != "" && input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	schemas, err := usecases.NewSchemaRegistry(tt, cfg.Validation, log)
+	if err != nil {
+		return err
+	}
+
+	opts.Format = transferFormat(opts.Format, input)
+	ctx := utils.ContextWithActor(context.Background(), cliActor())
+	report, importErr := usecases.NewTransferUseCase(tt, schemas, log).Import(ctx, in, opts)
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	return importErr
+}
+
+// transferFormat returns format, or the one matching the file extension
+// when format is empty. NDJSON is the default.
+func transferFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return domain.FormatCSV
+	case ".msgpack", ".mp":
+		return domain.FormatMsgpack
+	default:
+		return domain.FormatNDJSON
+	}
+}
+
+// cliActor attributes changes made by commands to the user running them.
+func cliActor() domain.Actor {
+	principal := "cli"
+	if u, err := user.Current(); err == nil {
+		principal += ":" + u.Username
+	}
+	return domain.Actor{Principal: principal}
+}
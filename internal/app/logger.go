@@ -1,57 +1,143 @@
 package app
 
 import (
+	"tarantool-app/config"
 	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type ZapLogger struct {
 	SugaredLogger *zap.SugaredLogger
+
+	level        zap.AtomicLevel
+	defaultLevel zapcore.Level // of the environment
+	redactor     *utils.Redactor
 }
 
-var _ interfaces.Logger = ZapLogger{} // ZapLogger must satisfy Logger
+var _ interfaces.Logger = ZapLogger{}   // ZapLogger must satisfy Logger
+var _ interfaces.LogLevel = ZapLogger{} // ZapLogger must satisfy LogLevel
+
+// NewLogger returns the development logger in the local environment and
+// the production one otherwise, writing to the output selected by cfg.Log
+// at cfg.App.LogLevel. Fields of every entry are redacted according to
+// cfg.Redaction.
+func NewLogger(cfg config.Config) (ZapLogger, error) {
+	loggerConfig := configureLogger(cfg.App.Environment)
+
+	var opts []zap.Option
+	switch cfg.Log.Output {
+	case config.LogOutputStdout:
+		loggerConfig.OutputPaths = []string{"stdout"}
+	case config.LogOutputFile:
+		opts = append(opts, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return rotatingCore(loggerConfig, cfg.Log)
+		}))
+	}
+
+	logger, err := loggerConfig.Build(opts...)
+	if err != nil {
+		return ZapLogger{}, err
+	}
 
-func NewLogger(env string) ZapLogger {
-	logger := configureLogger(env)
-	return ZapLogger{SugaredLogger: logger.Sugar()}
+	l := ZapLogger{
+		SugaredLogger: logger.Sugar(),
+		level:         loggerConfig.Level,
+		defaultLevel:  loggerConfig.Level.Level(),
+		redactor:      utils.NewRedactor(cfg.Redaction),
+	}
+	return l, l.SetLevel(cfg.App.LogLevel)
 }
 
-func configureLogger(env string) *zap.Logger {
+// rotatingCore writes to a file rotated by size and age instead of the
+// output paths of loggerConfig.
+func rotatingCore(loggerConfig zap.Config, cfg config.LogConfig) zapcore.Core {
+	encoder := zapcore.NewJSONEncoder(loggerConfig.EncoderConfig)
+	if loggerConfig.Encoding == "console" {
+		encoder = zapcore.NewConsoleEncoder(loggerConfig.EncoderConfig)
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   cfg.File,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     int(cfg.MaxAge.Hours() / 24),
+		Compress:   cfg.Compress,
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.AddSync(file), loggerConfig.Level)
+	if s := loggerConfig.Sampling; s != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, s.Initial, s.Thereafter)
+	}
+	return core
+}
+
+// Level returns the current level of the logger.
+func (l ZapLogger) Level() string {
+	return l.level.Level().String()
+}
+
+// SetLevel changes the level of the logger and every logger derived from
+// it, restoring the default of the environment when level is empty.
+func (l ZapLogger) SetLevel(level string) error {
+	if level == "" {
+		l.level.SetLevel(l.defaultLevel)
+		return nil
+	}
+
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
+func configureLogger(env string) zap.Config {
 	var loggerConfig zap.Config
 
 	switch env {
 	case "local":
 		loggerConfig = zap.NewDevelopmentConfig()
-	case "prod":
+	default:
 		loggerConfig = zap.NewProductionConfig()
 	}
 
 	loggerConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	loggerConfig.DisableCaller = true
 
-	return zap.Must(loggerConfig.Build())
+	return loggerConfig
 }
 
 func (l ZapLogger) Info(msg string, keysAndValues ...any) {
-	l.SugaredLogger.Infow(msg, keysAndValues...)
+	l.SugaredLogger.Infow(msg, l.redactor.Fields(keysAndValues)...)
 }
 
 func (l ZapLogger) Debug(msg string, keysAndValues ...any) {
-	l.SugaredLogger.Debugw(msg, keysAndValues...)
+	l.SugaredLogger.Debugw(msg, l.redactor.Fields(keysAndValues)...)
 }
 
 func (l ZapLogger) Warn(msg string, keysAndValues ...any) {
-	l.SugaredLogger.Warnw(msg, keysAndValues...)
+	l.SugaredLogger.Warnw(msg, l.redactor.Fields(keysAndValues)...)
 }
 
 func (l ZapLogger) Error(msg string, keysAndValues ...any) {
-	l.SugaredLogger.Errorw(msg, keysAndValues...)
+	l.SugaredLogger.Errorw(msg, l.redactor.Fields(keysAndValues)...)
 }
 
 func (l ZapLogger) Fatal(msg string, keysAndValues ...any) {
-	l.SugaredLogger.Fatalw(msg, keysAndValues...)
+	l.SugaredLogger.Fatalw(msg, l.redactor.Fields(keysAndValues)...)
+}
+
+// With returns a logger adding the key-value pairs to every entry. It
+// shares the level of l.
+func (l ZapLogger) With(keysAndValues ...any) interfaces.Logger {
+	l.SugaredLogger = l.SugaredLogger.With(l.redactor.Fields(keysAndValues)...)
+	return l
 }
 
 func (l ZapLogger) Sync() {
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+	"text/tabwriter"
+	"time"
+)
+
+var errMigrateUsage = errors.New("usage: tarantool-app migrate status | up [version] | down <version>")
+
+// Migrate runs `migrate` subcommands against the configured instance as
+// the migrations user and prints the affected migrations.
+func Migrate(src config.Source, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	cfg, log, tt, err := connectAs(src, config.Config.Migrator)
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+	defer tt.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Migrations.Timeout)
+	defer cancel()
+
+	migrations := usecases.NewMigrationUseCase(tt, repository.RequiredSchemaVersion, log)
+
+	var result []domain.Migration
+	switch command, rest := args[0], args[1:]; command {
+	case "status":
+		result, err = migrations.Status(ctx)
+	case "up":
+		var target uint64
+		if len(rest) > 0 {
+			if target, err = strconv.ParseUint(rest[0], 10, 64); err != nil {
+				return fmt.Errorf("invalid version %q", rest[0])
+			}
+		}
+		result, err = migrations.Up(ctx, target)
+	case "down":
+		if len(rest) == 0 {
+			return errMigrateUsage
+		}
+		target, parseErr := strconv.ParseUint(rest[0], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid version %q", rest[0])
+		}
+		result, err = migrations.Down(ctx, target)
+	default:
+		return errMigrateUsage
+	}
+	if err != nil {
+		return err
+	}
+
+	return printMigrations(out, result)
+}
+
+// applyMigrations applies every pending migration within the migrations
+// timeout, over a connection of its own as the migrations user.
+func applyMigrations(ctx context.Context, cfg config.Config, log interfaces.Logger) error {
+	tt, err := repository.NewTarantoolRepository(cfg.Migrator(), log)
+	if err != nil {
+		return err
+	}
+	defer tt.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Migrations.Timeout)
+	defer cancel()
+
+	_, err = usecases.NewMigrationUseCase(tt, repository.RequiredSchemaVersion, log).Up(ctx, 0)
+	return err
+}
+
+func printMigrations(out io.Writer, migrations []domain.Migration) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, migration := range migrations {
+		applied := "pending"
+		if migration.Applied() {
+			applied = migration.AppliedTime().UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", migration.Version, migration.Name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"tarantool-app/config"
+	"time"
+)
+
+// reloader re-reads the config on SIGHUP and when the file changes, and
+// applies the settings listed in config.Reloadable. Other changes are
+// logged as requiring a restart.
+type reloader struct {
+	src     config.Source
+	log     ZapLogger
+	current config.Config
+	apply   func(old, cfg config.Config)
+}
+
+func newReloader(src config.Source, cfg config.Config, log ZapLogger, apply func(old, cfg config.Config)) *reloader {
+	return &reloader{src: src, log: log, current: cfg, apply: apply}
+}
+
+// Run reloads until ctx is done. The file is checked every
+// app.reload_interval, never when it is zero.
+func (r *reloader) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval := r.current.App.ReloadInterval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	version := r.fileVersion()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			version = r.fileVersion()
+			r.reload("signal")
+		case <-tick:
+			if v := r.fileVersion(); v != version {
+				version = v
+				r.reload("file")
+			}
+		}
+	}
+}
+
+// fileVersion identifies the contents of the config file cheaply.
+func (r *reloader) fileVersion() string {
+	info, err := os.Stat(r.src.Path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", info.ModTime(), info.Size())
+}
+
+func (r *reloader) reload(trigger string) {
+	cfg, err := r.src.Load()
+	if err != nil {
+		r.log.Error("Config reload failed, keeping the running config",
+			"trigger", trigger,
+			"error", err,
+		)
+		return
+	}
+
+	var applied, ignored []string
+	for _, path := range r.current.Changed(cfg) {
+		if reloadable(path) {
+			applied = append(applied, path)
+		} else {
+			ignored = append(ignored, path)
+		}
+	}
+
+	r.apply(r.current, cfg)
+	r.current = cfg
+
+	r.log.Info("Config reloaded",
+		"trigger", trigger,
+		"applied", applied,
+		"restart_required", ignored,
+	)
+}
+
+func reloadable(path string) bool {
+	for _, setting := range config.Reloadable {
+		if path == setting || strings.HasSuffix(setting, ".") && strings.HasPrefix(path, setting) {
+			return true
+		}
+	}
+	return false
+}
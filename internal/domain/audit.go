@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Operations recorded in the audit trail.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+
+	AuditBlobCreate = "blob_create"
+	AuditBlobUpdate = "blob_update"
+	AuditBlobDelete = "blob_delete"
+)
+
+// PrincipalSystem is recorded for changes made on behalf of no client,
+// e.g. by background jobs.
+const PrincipalSystem = "system"
+
+// Actor is the client on whose behalf a value is changed.
+type Actor struct {
+	Principal string `msgpack:"principal"`
+	ClientIP  string `msgpack:"client_ip"`
+	RequestID string `msgpack:"request_id"`
+	// ValueHash is the hash of the plain value written, set by the
+	// repository for writes of a whole value.
+	ValueHash string `msgpack:"value_hash,omitempty"`
+}
+
+// AuditEntry records a change of the value of Key, or of its blob. The
+// hashes are SHA-256 of the plain value, or of the blob metadata, before
+// and after the change, empty when there was none. Values changed in place,
+// e.g. by an increment, are hashed in their stored form. Field order matches the
+// `kv_audit` space format.
+type AuditEntry struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	ID        uint64   `json:"id"`
+	Key       string   `json:"key"`
+	Op        string   `json:"op"`
+	At        int64    `json:"at"` // milliseconds since the epoch
+	Principal string   `json:"principal"`
+	ClientIP  string   `json:"client_ip"`
+	RequestID string   `json:"request_id"`
+	OldHash   string   `json:"old_hash,omitempty"`
+	NewHash   string   `json:"new_hash,omitempty"`
+}
+
+func (e AuditEntry) Time() time.Time {
+	return time.UnixMilli(e.At)
+}
+
+// AuditQuery selects the entries of Key, of every key when empty, made in
+// [Since, Until), either bound being open when zero. After is the ID of
+// the last entry of the previous page.
+type AuditQuery struct {
+	Key   string
+	Since time.Time
+	Until time.Time
+	After uint64
+	Limit int
+}
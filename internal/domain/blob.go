@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// BlobMeta describes a large value stored as a sequence of chunks.
+// Field order matches the `kv_blobs` space format.
+type BlobMeta struct {
+	_msgpack    struct{} `msgpack:",as_array"` //nolint:unused
+	Key         string   `json:"key"`
+	Generation  string   `json:"generation"`
+	Size        uint64   `json:"size"`
+	ChunkSize   uint64   `json:"chunk_size"`
+	Chunks      uint64   `json:"chunks"`
+	ContentType string   `json:"content_type"`
+	UpdatedAt   int64    `json:"updated_at"`
+}
+
+func (m BlobMeta) ModTime() time.Time {
+	return time.Unix(m.UpdatedAt, 0)
+}
+
+// BlobChunk is a single piece of a blob generation.
+// Field order matches the `kv_chunks` space format.
+type BlobChunk struct {
+	_msgpack   struct{} `msgpack:",as_array"` //nolint:unused
+	Key        string
+	Generation string
+	Seq        uint64
+	Data       []byte
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Types of the key parts of collections, named after the Tarantool field
+// types. Parsed parts are string, uint64, int64 and uuid.UUID values.
+const (
+	KeyPartString   = "string"
+	KeyPartUnsigned = "unsigned"
+	KeyPartInteger  = "integer"
+	KeyPartUUID     = "uuid"
+)
+
+// DefaultCollection is the collection of /kv, keyed by a single string and
+// served under /collections/kv as well. Its values go through schemas,
+// compression, encryption, audit, history, the trash and tiering.
+const DefaultCollection = "kv"
+
+// Storage engines of collections.
+const (
+	EngineMemtx = "memtx" // in memory
+	EngineVinyl = "vinyl" // on disk
+)
+
+// Types of the paths indexed by the secondary indexes of collections.
+var indexTypes = []string{"string", "unsigned", "integer", "number", "boolean"}
+
+var (
+	ErrCollectionNotFound = errors.New("404 collection not found")
+	ErrInvalidCollection  = errors.New("400 invalid collection")
+)
+
+// collectionName is the name of a collection space, DefaultCollection and
+// "kv_" being reserved for the spaces of the application.
+var collectionName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+type KeyPart struct {
+	Field string `json:"field" msgpack:"field"`
+	Type  string `json:"type" msgpack:"type"`
+}
+
+// CollectionIndex is a secondary index of a collection over a path inside
+// the value, e.g. "status" or "tags[*]" for every element of an array.
+type CollectionIndex struct {
+	Name   string `json:"name" msgpack:"name"`
+	Path   string `json:"path" msgpack:"path"`
+	Type   string `json:"type" msgpack:"type"`
+	Unique bool   `json:"unique,omitempty" msgpack:"unique"`
+}
+
+// Collection is a space of values keyed by the typed fields of Key, in
+// the order of the primary index. Collections declared in the
+// configuration use the memtx engine and have no secondary indexes.
+type Collection struct {
+	Name    string            `json:"name"`
+	Engine  string            `json:"engine"`
+	Key     []KeyPart         `json:"key"`
+	Indexes []CollectionIndex `json:"indexes"`
+}
+
+// Validate checks the definition of a new collection.
+func (c Collection) Validate() error {
+	if !collectionName.MatchString(c.Name) || c.Name == DefaultCollection || strings.HasPrefix(c.Name, "kv_") {
+		return fmt.Errorf("%w: name must be lowercase letters, digits and underscores, not %s nor starting with kv_, got %q", ErrInvalidCollection, DefaultCollection, c.Name)
+	}
+	if c.Engine != EngineMemtx && c.Engine != EngineVinyl {
+		return fmt.Errorf("%w: engine must be %s or %s, got %q", ErrInvalidCollection, EngineMemtx, EngineVinyl, c.Engine)
+	}
+
+	if len(c.Key) == 0 {
+		return fmt.Errorf("%w: key must have at least one part", ErrInvalidCollection)
+	}
+	fields := make(map[string]bool, len(c.Key))
+	for _, part := range c.Key {
+		switch {
+		case part.Field == "" || part.Field == "value":
+			return fmt.Errorf("%w: key part field must be set and not be value", ErrInvalidCollection)
+		case fields[part.Field]:
+			return fmt.Errorf("%w: key part %q is declared twice", ErrInvalidCollection, part.Field)
+		case describeKeyPart(part.Type) == "":
+			return fmt.Errorf("%w: key part %s must be of type string, unsigned, integer or uuid, got %q", ErrInvalidCollection, part.Field, part.Type)
+		}
+		fields[part.Field] = true
+	}
+
+	names := map[string]bool{"primary": true}
+	for _, index := range c.Indexes {
+		multikey := strings.Contains(index.Path, "[*]")
+		switch {
+		case !collectionName.MatchString(index.Name):
+			return fmt.Errorf("%w: index name must be lowercase letters, digits and underscores, got %q", ErrInvalidCollection, index.Name)
+		case names[index.Name]:
+			return fmt.Errorf("%w: index %q is declared twice", ErrInvalidCollection, index.Name)
+		case index.Path == "":
+			return fmt.Errorf("%w: index %s must have a path", ErrInvalidCollection, index.Name)
+		case !slices.Contains(indexTypes, index.Type):
+			return fmt.Errorf("%w: index %s must be of type %s, got %q", ErrInvalidCollection, index.Name, strings.Join(indexTypes, ", "), index.Type)
+		case multikey && index.Unique:
+			return fmt.Errorf("%w: multikey index %s cannot be unique", ErrInvalidCollection, index.Name)
+		case multikey && c.Engine != EngineMemtx:
+			return fmt.Errorf("%w: multikey index %s needs the %s engine", ErrInvalidCollection, index.Name, EngineMemtx)
+		}
+		names[index.Name] = true
+	}
+	return nil
+}
+
+// Index returns the secondary index of the collection with the name.
+func (c Collection) Index(name string) (CollectionIndex, bool) {
+	for _, index := range c.Indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return CollectionIndex{}, false
+}
+
+// ParseKey parses the leading parts of a key of the collection from their
+// text form, e.g. the segments of a URL path. Fewer texts than parts make
+// a partial key, which selects a range of the primary index.
+func (c Collection) ParseKey(texts []string) ([]any, error) {
+	if len(texts) > len(c.Key) {
+		return nil, fmt.Errorf("%w: collection %s has a key of %d parts, got %d", ErrInvalidKey, c.Name, len(c.Key), len(texts))
+	}
+
+	key := make([]any, len(texts))
+	for i, text := range texts {
+		part, err := c.Key[i].parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be %s, got %q", ErrInvalidKey, c.Key[i].Field, describeKeyPart(c.Key[i].Type), text)
+		}
+		key[i] = part
+	}
+	return key, nil
+}
+
+func (p KeyPart) parse(text string) (any, error) {
+	switch p.Type {
+	case KeyPartUnsigned:
+		return strconv.ParseUint(text, 10, 64)
+	case KeyPartInteger:
+		return strconv.ParseInt(text, 10, 64)
+	case KeyPartUUID:
+		return uuid.Parse(text)
+	default:
+		// A leading underscore is reserved for the API, e.g. _query.
+		if text == "" || strings.HasPrefix(text, "_") {
+			return nil, fmt.Errorf("empty or reserved string")
+		}
+		return text, nil
+	}
+}
+
+func describeKeyPart(kind string) string {
+	switch kind {
+	case KeyPartUnsigned:
+		return "an unsigned integer"
+	case KeyPartInteger:
+		return "an integer"
+	case KeyPartUUID:
+		return "a UUID"
+	case KeyPartString:
+		return "a non-empty string not starting with _"
+	default:
+		return ""
+	}
+}
+
+// FormatKeyPart returns the text form of a parsed key part.
+func FormatKeyPart(part any) string {
+	switch v := part.(type) {
+	case string:
+		return v
+	case uuid.UUID:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Item is a value of a collection along with the parts of its key.
+type Item struct {
+	Key   []any          `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// ItemPage is one page of the items matching a query. Next is empty on the
+// last page.
+type ItemPage struct {
+	Items []Item
+	Next  []byte
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCollectionParseKey(t *testing.T) {
+	collection := Collection{
+		Name: "events",
+		Key: []KeyPart{
+			{Field: "tenant", Type: KeyPartString},
+			{Field: "seq", Type: KeyPartUnsigned},
+			{Field: "offset", Type: KeyPartInteger},
+			{Field: "id", Type: KeyPartUUID},
+		},
+	}
+	id := uuid.MustParse("2b4b1d6e-58f2-4c2c-9a5e-3f1f0c7a9d10")
+
+	tests := []struct {
+		name  string
+		texts []string
+		want  []any
+		err   bool
+	}{
+		{"no parts", nil, []any{}, false},
+		{"leading part", []string{"acme"}, []any{"acme"}, false},
+		{"full key", []string{"acme", "10", "-3", id.String()}, []any{"acme", uint64(10), int64(-3), id}, false},
+		{"numbers are not padded", []string{"acme", "007"}, []any{"acme", uint64(7)}, false},
+		{"too many parts", []string{"acme", "10", "-3", id.String(), "extra"}, nil, true},
+		{"empty string", []string{""}, nil, true},
+		{"reserved string", []string{"_query"}, nil, true},
+		{"negative unsigned", []string{"acme", "-1"}, nil, true},
+		{"unsigned overflow", []string{"acme", "18446744073709551616"}, nil, true},
+		{"not an integer", []string{"acme", "1", "1.5"}, nil, true},
+		{"not a uuid", []string{"acme", "1", "1", "42"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collection.ParseKey(tt.texts)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidKey) {
+					t.Fatalf("ParseKey(%q) error %v, want ErrInvalidKey", tt.texts, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseKey(%q) error %v", tt.texts, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseKey(%q) = %#v, want %#v", tt.texts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatKeyPart(t *testing.T) {
+	collection := Collection{Key: []KeyPart{
+		{Field: "tenant", Type: KeyPartString},
+		{Field: "seq", Type: KeyPartUnsigned},
+		{Field: "offset", Type: KeyPartInteger},
+		{Field: "id", Type: KeyPartUUID},
+	}}
+	texts := []string{"acme", "10", "-3", "2b4b1d6e-58f2-4c2c-9a5e-3f1f0c7a9d10"}
+
+	key, err := collection.ParseKey(texts)
+	if err != nil {
+		t.Fatalf("ParseKey(%q) error %v", texts, err)
+	}
+	for i, part := range key {
+		if got := FormatKeyPart(part); got != texts[i] {
+			t.Errorf("FormatKeyPart(%#v) = %q, want %q", part, got, texts[i])
+		}
+	}
+}
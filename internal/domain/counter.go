@@ -0,0 +1,13 @@
+package domain
+
+// Increment adds Delta to the number at Path inside the value of Key.
+// Min and Max are optional inclusive bounds of the result. Validate, when
+// set, checks the resulting value before it is stored.
+type Increment struct {
+	Key      string
+	Path     []string
+	Delta    float64
+	Min      *float64
+	Max      *float64
+	Validate func(value map[string]any) error
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Version is a value Key had, numbered from 1 in the order of changes.
+// A deleted key leaves a version without a value.
+type Version struct {
+	Key     string         `json:"key"`
+	Version uint64         `json:"version"`
+	At      int64          `json:"at"` // milliseconds since the epoch
+	Value   map[string]any `json:"value,omitempty"`
+	Deleted bool           `json:"deleted,omitempty"`
+}
+
+func (v Version) Time() time.Time {
+	return time.UnixMilli(v.At)
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Keys are paths of segments separated by KeySeparator, e.g.
+// "tenant/orders/123".
+const (
+	KeySeparator = "/"
+	MaxKeyLength = 1024 // bytes
+)
+
+var ErrInvalidKey = errors.New("400 invalid key")
+
+// Subresources are the names /kv/:id/<name> routes to instead of a key, so
+// keys with more than one segment cannot end with one of them.
+var Subresources = []string{"blob", "decr", "history", "incr", "restore", "undelete"}
+
+// NormalizeKey returns the NFC form of key, so that keys spelled with
+// composed and decomposed characters name the same value.
+func NormalizeKey(key string) string {
+	return norm.NFC.String(key)
+}
+
+// ValidateKey checks the rules new keys must follow: at most MaxKeyLength
+// bytes of printable UTF-8, no empty segments and no "." or ".." segments.
+// Segments starting with an underscore are reserved for the API, e.g.
+// /kv/_query, and so are Subresources as the last of several segments.
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("%w: key is required", ErrInvalidKey)
+	case len(key) > MaxKeyLength:
+		return fmt.Errorf("%w: key is longer than %d bytes", ErrInvalidKey, MaxKeyLength)
+	case !utf8.ValidString(key):
+		return fmt.Errorf("%w: key is not valid UTF-8", ErrInvalidKey)
+	case strings.IndexFunc(key, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0:
+		return fmt.Errorf("%w: key contains unprintable characters", ErrInvalidKey)
+	}
+
+	segments := strings.Split(key, KeySeparator)
+	for _, segment := range segments {
+		switch {
+		case segment == "":
+			return fmt.Errorf("%w: key has an empty segment", ErrInvalidKey)
+		case segment == "." || segment == "..":
+			return fmt.Errorf("%w: key has a %q segment", ErrInvalidKey, segment)
+		case strings.HasPrefix(segment, "_"):
+			return fmt.Errorf("%w: key segment %q starts with a reserved underscore", ErrInvalidKey, segment)
+		}
+	}
+	if last := segments[len(segments)-1]; len(segments) > 1 && slices.Contains(Subresources, last) {
+		return fmt.Errorf("%w: key ends with the reserved segment %q", ErrInvalidKey, last)
+	}
+	return nil
+}
+
+// ChildPrefix returns the prefix of the keys below path, empty for the
+// root.
+func ChildPrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + KeySeparator
+}
+
+// Child is an immediate child of a path: a key, the parent of deeper keys,
+// or both. Field order matches the result of `kv_children`.
+type Child struct {
+	_msgpack    struct{} `msgpack:",as_array"` //nolint:unused
+	Name        string   `json:"name"`
+	Key         string   `json:"key"`
+	HasValue    bool     `json:"has_value"`
+	HasChildren bool     `json:"has_children"`
+}
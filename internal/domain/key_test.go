@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{"single segment", "orders", true},
+		{"path", "tenant/orders/123", true},
+		{"longest key", strings.Repeat("a", MaxKeyLength), true},
+		{"subresource name as the only segment", "blob", true},
+		{"subresource name inside the key", "reports/history/2024", true},
+		{"underscore inside a segment", "orders/a_b", true},
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", MaxKeyLength+1), false},
+		{"invalid UTF-8", "orders/\xff", false},
+		{"unprintable", "orders/\n1", false},
+		{"leading slash", "/orders", false},
+		{"trailing slash", "orders/", false},
+		{"double slash", "orders//1", false},
+		{"dot segment", "orders/./1", false},
+		{"dot-dot segment", "orders/../1", false},
+		{"reserved underscore", "_query", false},
+		{"reserved underscore in a later segment", "orders/_1", false},
+		{"ends with blob", "a/blob", false},
+		{"ends with history", "reports/history", false},
+		{"ends with incr", "x/incr", false},
+		{"ends with undelete", "x/undelete", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKey(tt.key)
+			if tt.valid && err != nil {
+				t.Fatalf("ValidateKey(%q) = %v, want nil", tt.key, err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidKey) {
+				t.Fatalf("ValidateKey(%q) = %v, want ErrInvalidKey", tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Lock is a lease held by Owner until ExpiresAt. Token is a fencing token
+// that increases with every new acquisition of any lock.
+// Field order matches the `kv_locks` space format.
+type Lock struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	Name      string   `json:"name"`
+	Owner     string   `json:"owner"`
+	Token     uint64   `json:"token"`
+	ExpiresAt int64    `json:"expires_at"` // milliseconds since the epoch
+}
+
+func (l Lock) Expires() time.Time {
+	return time.UnixMilli(l.ExpiresAt)
+}
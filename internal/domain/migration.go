@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Migration is a versioned schema step defined in `migrations.lua`.
+// AppliedAt is zero while the step is pending.
+type Migration struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	Version   uint64   `json:"version"`
+	Name      string   `json:"name"`
+	AppliedAt int64    `json:"applied_at"` // seconds since the epoch
+}
+
+func (m Migration) Applied() bool {
+	return m.AppliedAt != 0
+}
+
+func (m Migration) AppliedTime() time.Time {
+	return time.Unix(m.AppliedAt, 0)
+}
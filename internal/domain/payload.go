@@ -2,41 +2,54 @@ package domain
 
 import (
 	"fmt"
-	"reflect"
 
 	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
 )
 
 type Payload struct {
 	Key   string         `json:"key"`
 	Value map[string]any `json:"value"`
+
+	// Raw is the MessagePack encoding of Value as sent by a client, written
+	// to Tarantool as is instead of encoding Value again. Nil for values
+	// decoded from other encodings.
+	Raw msgpack.RawMessage `json:"-"`
 }
 
-func (p *Payload) EncodeMsgpack(e *msgpack.Encoder) error {
-	payloadLength := reflect.TypeOf(Payload{}).NumField()
+// payloadFields is the length of the tuple form of a payload.
+const payloadFields = 2
 
-	if err := e.EncodeArrayLen(payloadLength); err != nil {
+func (p *Payload) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(payloadFields); err != nil {
 		return err
 	}
 	if err := e.EncodeString(p.Key); err != nil {
 		return err
 	}
-	if err := e.EncodeMap(p.Value); err != nil {
-		return err
+	if p.Raw != nil {
+		return e.Encode(p.Raw)
 	}
-	return nil
+	return e.EncodeMap(p.Value)
 }
 
+// DecodeMsgpack accepts both the tuple form `[key, value]` used by Tarantool
+// and the map form `{"key": ..., "value": ...}` sent by HTTP clients.
 func (p *Payload) DecodeMsgpack(d *msgpack.Decoder) error {
-	var err error
+	code, err := d.PeekCode()
+	if err != nil {
+		return err
+	}
+	if msgpcode.IsFixedMap(code) || code == msgpcode.Map16 || code == msgpcode.Map32 {
+		return p.decodeMsgpackMap(d)
+	}
+
 	var structLength int
 	if structLength, err = d.DecodeArrayLen(); err != nil {
 		return err
 	}
 
-	payloadLength := reflect.TypeOf(Payload{}).NumField()
-
-	if structLength != payloadLength {
+	if structLength != payloadFields {
 		return fmt.Errorf("array len doesn't match: %d", structLength)
 	}
 	if p.Key, err = d.DecodeString(); err != nil {
@@ -47,3 +60,36 @@ func (p *Payload) DecodeMsgpack(d *msgpack.Decoder) error {
 	}
 	return nil
 }
+
+func (p *Payload) decodeMsgpackMap(d *msgpack.Decoder) error {
+	mapLength, err := d.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+
+	for range mapLength {
+		field, err := d.DecodeString()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case "key":
+			if p.Key, err = d.DecodeString(); err != nil {
+				return err
+			}
+		case "value":
+			if p.Raw, err = d.DecodeRaw(); err != nil {
+				return err
+			}
+			if err = msgpack.Unmarshal(p.Raw, &p.Value); err != nil {
+				return err
+			}
+		default:
+			if err = d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
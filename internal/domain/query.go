@@ -0,0 +1,16 @@
+package domain
+
+// Query selects values whose indexed path equals Key.
+// After is the position returned as Page.Next by the previous page.
+type Query struct {
+	Index string
+	Key   any
+	Limit int
+	After []byte
+}
+
+// Page is one page of query results. Next is empty on the last page.
+type Page struct {
+	Items []Payload
+	Next  []byte
+}
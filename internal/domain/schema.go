@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema is a JSON Schema enforced for values of keys with the given prefix.
+// Field order matches the `kv_schemas` space format.
+type Schema struct {
+	_msgpack struct{}       `msgpack:",as_array"` //nolint:unused
+	Prefix   string         `json:"prefix"`
+	Document map[string]any `json:"schema"`
+}
+
+// Violation points to the part of a value that does not match its schema.
+// Path is a JSON pointer into the value.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Prefix     string
+	Violations []Violation
+}
+
+var _ error = &ValidationError{} // ValidationError must satisfy error
+
+func (err *ValidationError) Error() string {
+	messages := make([]string, 0, len(err.Violations))
+	for _, v := range err.Violations {
+		messages = append(messages, fmt.Sprintf("%s: %s", v.Path, v.Message))
+	}
+	return fmt.Sprintf("value does not match schema %q: %s", err.Prefix, strings.Join(messages, "; "))
+}
@@ -0,0 +1,24 @@
+package domain
+
+type SQLColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// SQLResult holds the rows of a read-only SQL statement. Truncated is set
+// when the statement produced more rows than the requested limit.
+type SQLResult struct {
+	Columns   []SQLColumn
+	Rows      [][]any
+	Truncated bool
+}
+
+// SQLQuery is a read-only SQL statement with positional parameters.
+// MaxSteps bounds the virtual machine instructions Tarantool executes.
+type SQLQuery struct {
+	Statement string
+	Params    []any
+	Limit     int
+	MaxSteps  uint64
+}
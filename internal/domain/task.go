@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Task statuses of the work queues.
+const (
+	TaskReady  = "ready"
+	TaskTaken  = "taken"
+	TaskBuried = "buried"
+)
+
+// Task is a queued job. For taken tasks ReadyAt is the visibility deadline
+// after which the task is delivered again.
+// Field order matches the `kv_queue_tasks` space format.
+type Task struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	ID        uint64
+	Queue     string
+	Status    string
+	ReadyAt   int64 // milliseconds since the epoch
+	Attempts  uint64
+	CreatedAt int64 // milliseconds since the epoch
+	Payload   any
+}
+
+func (t Task) Ready() time.Time {
+	return time.UnixMilli(t.ReadyAt)
+}
+
+// Receipt identifies the latest take of the task: acknowledging, rejecting
+// or burying the task requires it, so a consumer whose visibility timeout
+// expired cannot act on a task delivered again.
+func (t Task) Receipt() uint64 {
+	return t.Attempts
+}
+
+func (t Task) Created() time.Time {
+	return time.UnixMilli(t.CreatedAt)
+}
@@ -0,0 +1,58 @@
+package domain
+
+// Formats of bulk export and import.
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatMsgpack = "msgpack"
+)
+
+// Conflict policies of an import for keys that already exist.
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictFail      = "fail"
+)
+
+// ImportOptions controls a bulk import. With DryRun nothing is written,
+// the report tells what would have happened.
+type ImportOptions struct {
+	Format    string
+	Policy    string
+	BatchSize int
+	DryRun    bool
+}
+
+// MaxImportFailures bounds the failures listed in an import report.
+const MaxImportFailures = 100
+
+type ImportFailure struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// ImportReport counts the outcome of every imported key. Failures lists
+// the first failed keys with their reasons.
+type ImportReport struct {
+	Inserted    int             `json:"inserted"`
+	Overwritten int             `json:"overwritten"`
+	Skipped     int             `json:"skipped"`
+	Failed      int             `json:"failed"`
+	Failures    []ImportFailure `json:"failures,omitempty"`
+	DryRun      bool            `json:"dry_run"`
+}
+
+func (r *ImportReport) AddFailure(key, reason string) {
+	r.Failed++
+	if len(r.Failures) < MaxImportFailures {
+		r.Failures = append(r.Failures, ImportFailure{Key: key, Error: reason})
+	}
+}
+
+func (r *ImportReport) AddWritten(overwritten bool) {
+	if overwritten {
+		r.Overwritten++
+	} else {
+		r.Inserted++
+	}
+}
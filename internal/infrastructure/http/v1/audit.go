@@ -0,0 +1,158 @@
+// Handlers for the audit trail.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	Handler interfaces.AuditUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.AuditHandler = AuditHandler{} // AuditHandler must satisfy interfaces.AuditHandler
+
+func NewAuditHandler(uc interfaces.AuditUseCase, log interfaces.Logger) AuditHandler {
+	return AuditHandler{Handler: uc, Logger: log}
+}
+
+// @Summary      Query the audit trail
+// @Description  Lists who changed values and when, oldest first, one page at a time. Entries carry the SHA-256 of the stored value before and after the change.
+// @Tags         admin
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        key    query  string  false  "Key, every key by default"
+// @Param        since  query  string  false  "RFC 3339 time or milliseconds since the epoch, inclusive"
+// @Param        until  query  string  false  "RFC 3339 time or milliseconds since the epoch, exclusive"
+// @Param        limit  query  int     false  "Page size, 100 by default"
+// @Param        after  query  int     false  "ID of the last entry of the previous page"
+// @Success      200 {object} map[string]interface{} "Entries and the ID to continue after"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/audit [get]
+func (ah AuditHandler) GetAudit(c *gin.Context) {
+	q, err := auditQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := ah.Handler.Query(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidAuditQuery) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, ah.Logger).Warn("Tarantool failed to query the audit trail",
+				"key", q.Key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	response := gin.H{"entries": entries}
+	if len(entries) > 0 {
+		response["after"] = entries[len(entries)-1].ID
+	}
+	respond(c, http.StatusOK, response)
+}
+
+// @Summary      Export the audit trail
+// @Description  Streams every entry matching the query as NDJSON, oldest first.
+// @Tags         admin
+// @Produce      application/x-ndjson
+// @Security     AdminToken
+// @Param        key    query  string  false  "Key, every key by default"
+// @Param        since  query  string  false  "RFC 3339 time or milliseconds since the epoch, inclusive"
+// @Param        until  query  string  false  "RFC 3339 time or milliseconds since the epoch, exclusive"
+// @Success      200 {file} binary "Audit entries"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/audit/export [get]
+func (ah AuditHandler) ExportAudit(c *gin.Context) {
+	q, err := auditQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", mimeNDJSON)
+	c.Header("Content-Disposition", `attachment; filename="audit.ndjson"`)
+
+	exported, err := ah.Handler.Export(c.Request.Context(), c.Writer, q)
+	switch {
+	case err == nil:
+	case c.Writer.Written():
+		// The status line is already sent, the client sees a truncated body.
+		requestLogger(c, ah.Logger).Warn("Audit export failed",
+			"key", q.Key,
+			"exported", exported,
+			"error", err,
+		)
+		c.Abort()
+	default:
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		if errors.Is(err, usecases.ErrInvalidAuditQuery) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		requestLogger(c, ah.Logger).Warn("Audit export failed",
+			"key", q.Key,
+			"error", err,
+		)
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+	}
+}
+
+// auditQuery reads the query parameters shared by the audit handlers.
+func auditQuery(c *gin.Context) (domain.AuditQuery, error) {
+	q := domain.AuditQuery{Key: c.Query("key")}
+
+	var err error
+	if q.Since, err = parseAuditTime(c.Query("since")); err != nil {
+		return q, fmt.Errorf("invalid since: %w", err)
+	}
+	if q.Until, err = parseAuditTime(c.Query("until")); err != nil {
+		return q, fmt.Errorf("invalid until: %w", err)
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil {
+			return q, errors.New("invalid limit")
+		}
+	}
+	if raw := c.Query("after"); raw != "" {
+		if q.After, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return q, errors.New("invalid after")
+		}
+	}
+	return q, nil
+}
+
+// parseAuditTime accepts RFC 3339 times and milliseconds since the epoch.
+// An empty string is the zero time, an open bound.
+func parseAuditTime(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,87 @@
+// Authentication middlewares.
+
+package v1
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireAdmin rejects requests without the admin bearer token.
+// All requests are rejected while the token is not configured.
+func requireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			respond(c, http.StatusForbidden, gin.H{"error": "403 admin API is disabled"})
+			c.Abort()
+			return
+		}
+
+		if !hasToken(c, token) {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			respond(c, http.StatusUnauthorized, gin.H{"error": "401 unauthorized"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requireService rejects requests carrying neither the service nor the
+// admin bearer token. All requests are rejected while neither is
+// configured.
+func requireService(service, admin string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if service == "" && admin == "" {
+			respond(c, http.StatusForbidden, gin.H{"error": "403 service API is disabled"})
+			c.Abort()
+			return
+		}
+
+		if !(service != "" && hasToken(c, service)) && !(admin != "" && hasToken(c, admin)) {
+			c.Header("WWW-Authenticate", `Bearer realm="service"`)
+			respond(c, http.StatusUnauthorized, gin.H{"error": "401 unauthorized"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Principals recorded in the audit trail.
+const (
+	principalAdmin     = "admin"
+	principalAnonymous = "anonymous"
+)
+
+// auditActor attributes changes made while serving the request to the
+// admin when the admin token is presented, to an anonymous client at the
+// request's address otherwise.
+func auditActor(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := domain.Actor{
+			Principal: principalAnonymous,
+			ClientIP:  c.ClientIP(),
+			RequestID: c.Writer.Header().Get(requestIDHeader),
+		}
+		if token != "" && hasToken(c, token) {
+			actor.Principal = principalAdmin
+		}
+
+		c.Request = c.Request.WithContext(utils.ContextWithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// hasToken reports whether the request carries the bearer token.
+func hasToken(c *gin.Context, token string) bool {
+	provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
@@ -0,0 +1,146 @@
+// Handlers for chunked large values.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlobHandler struct {
+	Handler interfaces.BlobUseCase
+	Logger  interfaces.Logger
+	MaxSize int64
+}
+
+var _ interfaces.BlobHandler = BlobHandler{} // BlobHandler must satisfy interfaces.BlobHandler
+
+func NewBlobHandler(uc interfaces.BlobUseCase, maxSize int64, log interfaces.Logger) BlobHandler {
+	return BlobHandler{Handler: uc, Logger: log, MaxSize: maxSize}
+}
+
+// @Summary      Download a large value
+// @Description  Streams the chunked value stored under the key. Supports Range and If-Range requests.
+// @Tags         blob
+// @Produce      octet-stream
+// @Param        id     path    string  true   "Key ID"
+// @Param        Range  header  string  false  "Byte range, e.g. bytes=0-1023"
+// @Success      200 {file} binary "Whole value"
+// @Success      206 {file} binary "Requested range"
+// @Failure      404 {object} map[string]interface{} "Key not found"
+// @Failure      416 {string} string "Range not satisfiable"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/blob [get]
+func (bh BlobHandler) GetBlob(c *gin.Context) {
+	key := c.Param("id")
+
+	meta, content, err := bh.Handler.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
+		} else {
+			requestLogger(c, bh.Logger).Warn("Tarantool failed to retrieve blob metadata",
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	c.Header("Content-Type", meta.ContentType)
+	c.Header("ETag", strconv.Quote(meta.Generation))
+	http.ServeContent(c.Writer, c.Request, "", meta.ModTime(), content)
+}
+
+// @Summary      Upload a large value
+// @Description  Streams the request body into chunks and atomically replaces the previous value.
+// @Tags         blob
+// @Accept       octet-stream
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id    path  string  true  "Key ID"
+// @Param        body  body  string  true  "Raw value"
+// @Success      200 {object} map[string]interface{} "Stored successfully"
+// @Failure      409 {object} map[string]interface{} "Concurrent or expired upload"
+// @Failure      413 {object} map[string]interface{} "Value is too large"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/blob [put]
+func (bh BlobHandler) PutBlob(c *gin.Context) {
+	key := c.Param("id")
+
+	contentType := c.ContentType()
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, bh.MaxSize)
+
+	meta, err := bh.Handler.Upload(c.Request.Context(), key, contentType, body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, domain.ErrInvalidKey):
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecases.ErrBlobTooLarge), errors.As(err, &maxBytesErr):
+			respond(c, http.StatusRequestEntityTooLarge, gin.H{"error": usecases.ErrBlobTooLarge.Error()})
+		case errors.Is(err, repository.ErrBlobIncomplete):
+			respond(c, http.StatusConflict, gin.H{"error": repository.ErrBlobIncomplete.Error()})
+		default:
+			requestLogger(c, bh.Logger).Warn("Tarantool failed to store blob",
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, blobResponse("stored", meta))
+}
+
+// @Summary      Delete a large value
+// @Description  Deletes the chunked value and all of its chunks.
+// @Tags         blob
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id  path  string  true  "Key ID"
+// @Success      200 {object} map[string]interface{} "Deleted successfully"
+// @Failure      404 {object} map[string]interface{} "Key not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/blob [delete]
+func (bh BlobHandler) DeleteBlob(c *gin.Context) {
+	key := c.Param("id")
+
+	meta, err := bh.Handler.Delete(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
+		} else {
+			requestLogger(c, bh.Logger).Warn("Tarantool failed to delete blob",
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, blobResponse("deleted", meta))
+}
+
+func blobResponse(message string, meta domain.BlobMeta) gin.H {
+	return gin.H{
+		"message":      message,
+		"key":          meta.Key,
+		"generation":   meta.Generation,
+		"size":         meta.Size,
+		"chunks":       meta.Chunks,
+		"content_type": meta.ContentType,
+	}
+}
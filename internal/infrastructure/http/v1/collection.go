@@ -0,0 +1,451 @@
+// Handlers for collections and their items.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+	Handler interfaces.CollectionUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.CollectionHandler = CollectionHandler{} // CollectionHandler must satisfy interfaces.CollectionHandler
+
+func NewCollectionHandler(uc interfaces.CollectionUseCase, log interfaces.Logger) CollectionHandler {
+	return CollectionHandler{Handler: uc, Logger: log}
+}
+
+// collectionRequest is the body of POST /admin/collections.
+type collectionRequest struct {
+	Name    string                   `json:"name"`
+	Engine  string                   `json:"engine"`
+	Key     []domain.KeyPart         `json:"key"`
+	Indexes []domain.CollectionIndex `json:"indexes"`
+}
+
+// updateRequest is the body of the item requests of collections.
+type updateRequest struct {
+	Value map[string]any `json:"value"`
+}
+
+// collectionRoute serves one method of /collections/:name/*key, like
+// keyRoute for /kv.
+type collectionRoute struct {
+	item     gin.HandlerFunc            // a full key
+	scan     gin.HandlerFunc            // a path ending with a slash
+	reserved map[string]gin.HandlerFunc // a single segment, e.g. _query
+}
+
+func (cr collectionRoute) handle(c *gin.Context) {
+	key, trailing, _ := itemPath(c)
+	switch {
+	case trailing && cr.scan != nil:
+		cr.scan(c)
+	case len(key) == 1 && cr.reserved[key[0]] != nil:
+		cr.reserved[key[0]](c)
+	default:
+		cr.item(c)
+	}
+}
+
+// itemPath returns the parts of the key in /collections/:name/*key, split
+// and unescaped like the keys under /kv, and whether the path ends with a
+// slash.
+func itemPath(c *gin.Context) ([]string, bool, error) {
+	escaped := strings.TrimPrefix(c.Request.URL.EscapedPath(), "/collections/"+c.Param("name")+"/")
+	return splitPath(escaped)
+}
+
+// itemKey is the text form of the key of an item, as in its path.
+func itemKey(item domain.Item) string {
+	parts := make([]string, len(item.Key))
+	for i, part := range item.Key {
+		parts[i] = domain.FormatKeyPart(part)
+	}
+	return joinPath(parts)
+}
+
+// itemError answers the errors common to the item handlers and tells
+// whether err was one of them.
+func itemError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrCollectionNotFound), errors.Is(err, repository.ErrNotFound):
+		respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidKey), errors.Is(err, domain.ErrInvalidCollection),
+		errors.Is(err, usecases.ErrInvalidScan), errors.Is(err, usecases.ErrInvalidQuery):
+		respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAlreadyExists), errors.Is(err, repository.ErrCollectionExists),
+		errors.Is(err, usecases.ErrCollectionDeclared), errors.Is(err, usecases.ErrCollectionDefault):
+		respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary      Define a collection
+// @Description  Creates the space of a new collection: its key fields, in the order of the primary index, followed by a map value. Secondary indexes cover paths inside the value; a path ending with [*] indexes every element of an array and needs the memtx engine. The key and indexes of a collection cannot change.
+// @Tags         collections
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        body  body  v1.collectionRequest  true  "Collection, the engine is memtx by default"
+// @Success      201 {object} map[string]interface{} "Created collection"
+// @Failure      400 {object} map[string]interface{} "Invalid collection"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      409 {object} map[string]interface{} "Collection already exists"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/collections [post]
+func (ch CollectionHandler) CreateCollection(c *gin.Context) {
+	var rq collectionRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	collection, err := ch.Handler.Create(c.Request.Context(), domain.Collection{
+		Name:    rq.Name,
+		Engine:  rq.Engine,
+		Key:     rq.Key,
+		Indexes: rq.Indexes,
+	})
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to create collection",
+				"collection", rq.Name,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{"message": "created", "collection": collection})
+}
+
+// @Summary      List collections
+// @Description  Lists the default collection of /kv, the collections declared in the configuration and those defined over HTTP, by name.
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Success      200 {object} map[string]interface{} "Collections"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/collections [get]
+func (ch CollectionHandler) ListCollections(c *gin.Context) {
+	collections, err := ch.Handler.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c, ch.Logger).Warn("Tarantool failed to list collections",
+			"error", err,
+		)
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"collections": collections})
+}
+
+// @Summary      Drop a collection
+// @Description  Drops a collection defined over HTTP together with its items. The default collection and those declared in the configuration cannot be dropped.
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string  true  "Collection name"
+// @Success      200 {object} map[string]interface{} "Dropped"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Collection not found"
+// @Failure      409 {object} map[string]interface{} "Collection is the default one or declared in the configuration"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/collections/{name} [delete]
+func (ch CollectionHandler) DropCollection(c *gin.Context) {
+	name := c.Param("name")
+
+	if err := ch.Handler.Drop(c.Request.Context(), name); err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to drop collection",
+				"collection", name,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "dropped", "collection": name})
+}
+
+// @Summary      Create an item of a collection
+// @Description  Stores the value under a new key, failing if the key exists.
+// @Tags         collections
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string            true  "Collection name"
+// @Param        key   path  string            true  "Key parts separated by slashes"
+// @Param        body  body  v1.updateRequest  true  "Value"
+// @Success      201 {object} map[string]interface{} "Created item"
+// @Failure      400 {object} map[string]interface{} "Invalid key or body"
+// @Failure      404 {object} map[string]interface{} "Collection not found"
+// @Failure      409 {object} map[string]interface{} "Key already exists"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/{key} [post]
+func (ch CollectionHandler) PostItem(c *gin.Context) {
+	name := c.Param("name")
+	key, _, err := itemPath(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	var body updateRequest
+	if err := bind(c, &body); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	item, err := ch.Handler.Insert(c.Request.Context(), name, key, body.Value)
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to create item",
+				"collection", name,
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{"message": "created", "collection": name, "key": item.Key, "value": item.Value})
+}
+
+// @Summary      Get an item of a collection
+// @Description  Returns the item with the key, one path segment per key part in the order declared for the collection, e.g. /collections/orders/acme/42 for a key of a string and an unsigned part.
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string  true  "Collection name"
+// @Param        key   path  string  true  "Key parts separated by slashes"
+// @Success      200 {object} map[string]interface{} "Item"
+// @Failure      400 {object} map[string]interface{} "Invalid key"
+// @Failure      404 {object} map[string]interface{} "Collection or item not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/{key} [get]
+func (ch CollectionHandler) GetItem(c *gin.Context) {
+	name := c.Param("name")
+	key, _, err := itemPath(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	item, err := ch.Handler.Get(c.Request.Context(), name, key)
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to retreive item",
+				"collection", name,
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"collection": name, "key": item.Key, "value": item.Value})
+}
+
+// @Summary      Put an item into a collection
+// @Description  Stores the value under the key, replacing the current item. Values are stored as they are: collections have no schemas, compression, encryption, audit, history or trash.
+// @Tags         collections
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string            true  "Collection name"
+// @Param        key   path  string            true  "Key parts separated by slashes"
+// @Param        body  body  v1.updateRequest  true  "Value"
+// @Success      200 {object} map[string]interface{} "Stored item"
+// @Failure      400 {object} map[string]interface{} "Invalid key or body"
+// @Failure      404 {object} map[string]interface{} "Collection not found"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/{key} [put]
+func (ch CollectionHandler) PutItem(c *gin.Context) {
+	name := c.Param("name")
+	key, _, err := itemPath(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	var body updateRequest
+	if err := bind(c, &body); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	item, err := ch.Handler.Put(c.Request.Context(), name, key, body.Value)
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to store item",
+				"collection", name,
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "stored", "collection": name, "key": item.Key, "value": item.Value})
+}
+
+// @Summary      Delete an item of a collection
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string  true  "Collection name"
+// @Param        key   path  string  true  "Key parts separated by slashes"
+// @Success      200 {object} map[string]interface{} "Deleted item"
+// @Failure      400 {object} map[string]interface{} "Invalid key"
+// @Failure      404 {object} map[string]interface{} "Collection or item not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/{key} [delete]
+func (ch CollectionHandler) DeleteItem(c *gin.Context) {
+	name := c.Param("name")
+	key, _, err := itemPath(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	item, err := ch.Handler.Delete(c.Request.Context(), name, key)
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to delete item",
+				"collection", name,
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "deleted", "collection": name, "key": item.Key, "value": item.Value})
+}
+
+// @Summary      Scan the items of a collection
+// @Description  Lists the items whose key starts with the leading key parts in the path, every item for /collections/{name}/, one page at a time in key order: numerically for integer parts and part by part for composite keys. The path ends with a slash.
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name    path   string  true   "Collection name"
+// @Param        prefix  path   string  true   "Leading key parts separated by slashes"
+// @Param        limit   query  int     false  "Page size, 100 by default"
+// @Param        after   query  string  false  "Full key of the last item of the previous page"
+// @Success      200 {object} map[string]interface{} "Items and the key to continue after"
+// @Failure      400 {object} map[string]interface{} "Invalid prefix or query"
+// @Failure      404 {object} map[string]interface{} "Collection not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/{prefix}/ [get]
+func (ch CollectionHandler) ScanItems(c *gin.Context) {
+	name := c.Param("name")
+	prefix, _, err := itemPath(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	var after []string
+	if raw := c.Query("after"); raw != "" {
+		if after, _, err = splitPath(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid after"})
+			return
+		}
+	}
+
+	items, err := ch.Handler.Scan(c.Request.Context(), name, prefix, after, limit)
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to scan items",
+				"collection", name,
+				"prefix", prefix,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if items == nil {
+		items = []domain.Item{}
+	}
+	response := gin.H{"collection": name, "items": items}
+	if len(items) > 0 {
+		response["after"] = itemKey(items[len(items)-1])
+	}
+	respond(c, http.StatusOK, response)
+}
+
+// @Summary      Query the items of a collection by an index
+// @Description  Selects the items whose path covered by the secondary index equals the given value, one page at a time.
+// @Tags         collections
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name    path   string  true   "Collection name"
+// @Param        index   query  string  true   "Secondary index"
+// @Param        eq      query  string  true   "Value to match"
+// @Param        limit   query  int     false  "Page size, 50 by default"
+// @Param        cursor  query  string  false  "Cursor returned by the previous page"
+// @Success      200 {object} map[string]interface{} "Matching items and the next cursor"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      404 {object} map[string]interface{} "Collection not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /collections/{name}/_query [get]
+func (ch CollectionHandler) QueryItems(c *gin.Context) {
+	name := c.Param("name")
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	page, err := ch.Handler.Query(c.Request.Context(), name, c.Query("index"), c.Query("eq"), limit, c.Query("cursor"))
+	if err != nil {
+		if !itemError(c, err) {
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to query items",
+				"collection", name,
+				"index", c.Query("index"),
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	items := page.Items
+	if items == nil {
+		items = []domain.Item{}
+	}
+	response := gin.H{"collection": name, "items": items}
+	if len(page.Next) > 0 {
+		response["cursor"] = usecases.EncodeCursor(page.Next)
+	}
+	respond(c, http.StatusOK, response)
+}
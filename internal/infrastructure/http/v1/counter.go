@@ -0,0 +1,108 @@
+// Handlers for atomic counters inside values.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CounterHandler struct {
+	Handler interfaces.CounterUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.CounterHandler = CounterHandler{} // CounterHandler must satisfy interfaces.CounterHandler
+
+func NewCounterHandler(uc interfaces.CounterUseCase, log interfaces.Logger) CounterHandler {
+	return CounterHandler{Handler: uc, Logger: log}
+}
+
+// counterRequest is the body of POST /kv/:id/incr and /kv/:id/decr.
+// Delta defaults to 1.
+type counterRequest struct {
+	Path  string   `json:"path"`
+	Delta *float64 `json:"delta"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+}
+
+// @Summary      Increment a number inside a value
+// @Description  Atomically adds delta to the number at path, creating the key and the path from zero if missing. The result must stay within the optional bounds.
+// @Tags         kv
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id    path  string              true  "Key ID"
+// @Param        body  body  v1.counterRequest  true  "Path, delta and optional inclusive bounds"
+// @Success      200 {object} map[string]interface{} "New value of the counter"
+// @Failure      400 {object} map[string]interface{} "Invalid request or schema violation"
+// @Failure      409 {object} map[string]interface{} "Not a number or out of bounds"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/incr [post]
+func (ch CounterHandler) Incr(c *gin.Context) {
+	ch.apply(c, 1)
+}
+
+// @Summary      Decrement a number inside a value
+// @Description  Atomically subtracts delta from the number at path, creating the key and the path from zero if missing. The result must stay within the optional bounds.
+// @Tags         kv
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id    path  string              true  "Key ID"
+// @Param        body  body  v1.counterRequest  true  "Path, delta and optional inclusive bounds"
+// @Success      200 {object} map[string]interface{} "New value of the counter"
+// @Failure      400 {object} map[string]interface{} "Invalid request or schema violation"
+// @Failure      409 {object} map[string]interface{} "Not a number or out of bounds"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/decr [post]
+func (ch CounterHandler) Decr(c *gin.Context) {
+	ch.apply(c, -1)
+}
+
+func (ch CounterHandler) apply(c *gin.Context, sign float64) {
+	key := c.Param("id")
+
+	var rq counterRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	delta := 1.0
+	if rq.Delta != nil {
+		delta = *rq.Delta
+	}
+
+	value, err := ch.Handler.Increment(c.Request.Context(), key, rq.Path, sign*delta, rq.Min, rq.Max)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			respond(c, http.StatusBadRequest, validationResponse(validationErr))
+		case errors.Is(err, usecases.ErrInvalidCounter), errors.Is(err, domain.ErrInvalidKey):
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrNotANumber), errors.Is(err, repository.ErrOutOfBounds):
+			respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			requestLogger(c, ch.Logger).Warn("Tarantool failed to update counter",
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"key":   key,
+		"path":  strings.TrimPrefix(rq.Path, "value."),
+		"value": value,
+	})
+}
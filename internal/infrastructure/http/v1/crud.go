@@ -24,33 +24,36 @@ func NewRequestHandler(uc interfaces.UserUseCase, log interfaces.Logger) AppHand
 }
 
 // @Summary      Get value by key
-// @Description  Retrieves the value for the specified key from the Tarantool database.
+// @Description  Retrieves the value for the specified key from the Tarantool database, or one of its kept versions.
 // @Tags         kv
-// @Accept       json
-// @Produce      json
-// @Param        id  path  string  true  "Key ID"
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id       path   string  true   "Key ID"
+// @Param        version  query  int     false  "Number of a kept version to read instead of the current value"
 // @Success      200 {object} map[string]interface{} "Success"
-// @Failure      404 {object} map[string]interface{} "Key not found"
+// @Failure      400 {object} map[string]interface{} "Invalid version"
+// @Failure      404 {object} map[string]interface{} "Key or version not found"
+// @Failure      410 {object} map[string]interface{} "Key is deleted in this version"
 // @Failure      500 {object} map[string]interface{} "Internal server error"
 // @Router       /kv/{id} [get]
 func (rh AppHandler) GetKV(c *gin.Context) {
 	rq := domain.Payload{Key: c.Param("id")}
 
-	resp, err := rh.Handler.Read(rq)
+	resp, err := rh.Handler.Read(c.Request.Context(), rq)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
 		} else {
-			rh.Logger.Warn("Tarantool failed to retreive data by key",
+			requestLogger(c, rh.Logger).Warn("Tarantool failed to retreive data by key",
 				"key", rq.Key,
-				err,
+				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"key":   resp.Key,
 		"value": resp.Value,
 	})
@@ -60,48 +63,55 @@ func (rh AppHandler) GetKV(c *gin.Context) {
 // @Summary      Create a new key-value pair
 // @Description  Creates a new key with the provided value in the Tarantool database.
 // @Tags         kv
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        body  body  domain.Payload  true  "Payload containing key and value"
 // @Success      201 {object} map[string]interface{} "Created successfully"
-// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      400 {object} map[string]interface{} "Invalid request or schema violation"
 // @Failure      409 {object} map[string]interface{} "Key already exists"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
 // @Failure      500 {object} map[string]interface{} "Internal server error"
 // @Router       /kv [post]
 func (rh AppHandler) PostKV(c *gin.Context) {
 	var rq domain.Payload
 
-	if err := c.ShouldBindJSON(&rq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON format"})
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
 		return
 	}
 
 	if rq.Key == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing key"})
+		respond(c, http.StatusBadRequest, gin.H{"error": "missing key"})
 		return
 	}
 
 	if len(rq.Value) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing value"})
+		respond(c, http.StatusBadRequest, gin.H{"error": "missing value"})
 		return
 	}
 
-	err := rh.Handler.Create(rq)
+	rq.Key = domain.NormalizeKey(rq.Key)
+	err := rh.Handler.Create(c.Request.Context(), rq)
 	if err != nil {
-		if errors.Is(err, repository.ErrAlreadyExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": repository.ErrAlreadyExists.Error()})
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			respond(c, http.StatusBadRequest, validationResponse(validationErr))
+		} else if errors.Is(err, domain.ErrInvalidKey) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrAlreadyExists) {
+			respond(c, http.StatusConflict, gin.H{"error": repository.ErrAlreadyExists.Error()})
 		} else {
-			rh.Logger.Warn("Tarantool failed to store data",
+			requestLogger(c, rh.Logger).Warn("Tarantool failed to store data",
 				"key", rq.Key,
 				"value", rq.Value,
-				err,
+				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	respond(c, http.StatusCreated, gin.H{
 		"message": "created",
 		"key":     rq.Key,
 		"value":   rq.Value,
@@ -112,44 +122,51 @@ func (rh AppHandler) PostKV(c *gin.Context) {
 // @Summary      Update value by key
 // @Description  Updates the value for the specified key in the Tarantool database.
 // @Tags         kv
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        id    path  string          true  "Key ID"
 // @Param        body  body  domain.Payload  true  "Payload containing updated value"
 // @Success      200 {object} map[string]interface{} "Updated successfully"
-// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      400 {object} map[string]interface{} "Invalid request or schema violation"
 // @Failure      404 {object} map[string]interface{} "Key not found"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
 // @Failure      500 {object} map[string]interface{} "Internal server error"
 // @Router       /kv/{id} [put]
 func (rh AppHandler) PutKV(c *gin.Context) {
-	rq := domain.Payload{Key: c.Param("id")}
+	var rq domain.Payload
 
-	if err := c.ShouldBindJSON(&rq.Value); err != nil || len(rq.Value) != 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON format"})
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
 		return
 	}
 
-	if rq.Value["value"] == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing value"})
+	if len(rq.Value) == 0 {
+		respond(c, http.StatusBadRequest, gin.H{"error": "missing value"})
 		return
 	}
 
-	err := rh.Handler.Update(rq)
+	// The key comes from the path, a key in the body is ignored.
+	rq.Key = c.Param("id")
+
+	err := rh.Handler.Update(c.Request.Context(), rq)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			respond(c, http.StatusBadRequest, validationResponse(validationErr))
+		} else if errors.Is(err, repository.ErrNotFound) {
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
 		} else {
-			rh.Logger.Warn("Tarantool failed to update data",
+			requestLogger(c, rh.Logger).Warn("Tarantool failed to update data",
 				"key", rq.Key,
 				"body", rq.Value,
-				err,
+				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"message": "updated",
 		"key":     rq.Key,
 		"value":   rq.Value,
@@ -158,11 +175,12 @@ func (rh AppHandler) PutKV(c *gin.Context) {
 }
 
 // @Summary      Delete key-value pair
-// @Description  Deletes the specified key and its value from the Tarantool database.
+// @Description  Deletes the specified key and its value from the Tarantool database. In soft-delete mode the key is moved to the trash instead. With recursive=true every key below the key is deleted as well, in batches rather than atomically.
 // @Tags         kv
-// @Accept       json
-// @Produce      json
-// @Param        id  path  string  true  "Key ID"
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id         path   string  true   "Key ID"
+// @Param        recursive  query  bool    false  "Delete the subtree below the key as well"
 // @Success      200 {object} map[string]interface{} "Deleted successfully"
 // @Failure      404 {object} map[string]interface{} "Key not found"
 // @Failure      500 {object} map[string]interface{} "Internal server error"
@@ -170,24 +188,90 @@ func (rh AppHandler) PutKV(c *gin.Context) {
 func (rh AppHandler) DeleteKV(c *gin.Context) {
 	rq := domain.Payload{Key: c.Param("id")}
 
-	resp, err := rh.Handler.Delete(rq)
+	resp, err := rh.Handler.Delete(c.Request.Context(), rq)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotFound.Error()})
 		} else {
-			rh.Logger.Warn("Tarantool failed to delete data",
+			requestLogger(c, rh.Logger).Warn("Tarantool failed to delete data",
 				"key", rq.Key,
-				err,
+				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"message": "deleted",
 		"key":     resp.Key,
 		"value":   resp.Value,
 	})
 	return //nolint:staticcheck
 }
+
+// @Summary      Undelete a key
+// @Description  Moves a key deleted in soft-delete mode back from the trash, as long as its retention has not expired and the key was not created again.
+// @Tags         kv
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id  path  string  true  "Key ID"
+// @Success      200 {object} map[string]interface{} "Undeleted successfully"
+// @Failure      404 {object} map[string]interface{} "Key is not in the trash"
+// @Failure      409 {object} map[string]interface{} "Key already exists"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/undelete [post]
+func (rh AppHandler) UndeleteKV(c *gin.Context) {
+	rq := domain.Payload{Key: c.Param("id")}
+
+	resp, err := rh.Handler.Undelete(c.Request.Context(), rq)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotInTrash):
+			respond(c, http.StatusNotFound, gin.H{"error": repository.ErrNotInTrash.Error()})
+		case errors.Is(err, repository.ErrAlreadyExists):
+			respond(c, http.StatusConflict, gin.H{"error": repository.ErrAlreadyExists.Error()})
+		default:
+			requestLogger(c, rh.Logger).Warn("Tarantool failed to undelete data",
+				"key", rq.Key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"message": "undeleted",
+		"key":     resp.Key,
+		"value":   resp.Value,
+	})
+}
+
+// ResolveKey returns the key a /kv path names: its NFC form, unless the key
+// was stored as given before keys were normalized.
+func (rh AppHandler) ResolveKey(c *gin.Context, key string) (string, error) {
+	normalized := domain.NormalizeKey(key)
+	if normalized == key {
+		return key, nil
+	}
+
+	if _, err := rh.Handler.Read(c.Request.Context(), domain.Payload{Key: key}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return normalized, nil
+		}
+		requestLogger(c, rh.Logger).Warn("Tarantool failed to look up an unnormalized key",
+			"key", key,
+			"error", err,
+		)
+		return "", err
+	}
+	return key, nil
+}
+
+func validationResponse(err *domain.ValidationError) gin.H {
+	return gin.H{
+		"error":   "value does not match schema",
+		"prefix":  err.Prefix,
+		"details": err.Violations,
+	}
+}
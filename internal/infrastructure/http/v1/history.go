@@ -0,0 +1,196 @@
+// Handlers for the versions of stored values.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryHandler struct {
+	Handler interfaces.HistoryUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.HistoryHandler = HistoryHandler{} // HistoryHandler must satisfy interfaces.HistoryHandler
+
+func NewHistoryHandler(uc interfaces.HistoryUseCase, log interfaces.Logger) HistoryHandler {
+	return HistoryHandler{Handler: uc, Logger: log}
+}
+
+// restoreRequest is the body of POST /kv/:id/restore.
+type restoreRequest struct {
+	Version uint64 `json:"version"`
+}
+
+// @Summary      List the versions of a value
+// @Description  Lists the versions kept of the key, newest first, one page at a time. A deleted key leaves a version marked as deleted.
+// @Tags         kv
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id      path   string  true   "Key ID"
+// @Param        limit   query  int     false  "Page size, 20 by default"
+// @Param        before  query  int     false  "Number of the last version of the previous page"
+// @Success      200 {object} map[string]interface{} "Versions and the number to continue before"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/history [get]
+func (hh HistoryHandler) GetHistory(c *gin.Context) {
+	key := c.Param("id")
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	var before uint64
+	if raw := c.Query("before"); raw != "" {
+		var err error
+		if before, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid before"})
+			return
+		}
+	}
+
+	versions, err := hh.Handler.Versions(c.Request.Context(), key, before, limit)
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidHistoryQuery) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, hh.Logger).Warn("Tarantool failed to list versions",
+				"key", key,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if versions == nil {
+		versions = []domain.Version{}
+	}
+	response := gin.H{"key": key, "versions": versions}
+	if len(versions) > 0 {
+		response["before"] = versions[len(versions)-1].Version
+	}
+	respond(c, http.StatusOK, response)
+}
+
+// GetVersion answers GET /kv/:id?version=N, documented with GetKV.
+func (hh HistoryHandler) GetVersion(c *gin.Context) {
+	key := c.Param("id")
+
+	number, err := strconv.ParseUint(c.Query("version"), 10, 64)
+	if err != nil || number == 0 {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	version, err := hh.Handler.Version(c.Request.Context(), key, number)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionNotFound) {
+			respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, hh.Logger).Warn("Tarantool failed to retreive version",
+				"key", key,
+				"version", number,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if version.Deleted {
+		respond(c, http.StatusGone, gin.H{"error": repository.ErrVersionDeleted.Error(), "version": version})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"key":     version.Key,
+		"value":   version.Value,
+		"version": version.Version,
+		"at":      version.At,
+	})
+}
+
+// withVersion routes GET /kv/:id to the version of the key when the
+// version query parameter is set, to the current value otherwise.
+func withVersion(current gin.HandlerFunc, hh interfaces.HistoryHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("version") != "" {
+			hh.GetVersion(c)
+			return
+		}
+		current(c)
+	}
+}
+
+// @Summary      Restore a version of a value
+// @Description  Atomically makes a kept version the current value of the key, recording it as the newest version. The value must satisfy the current schema of the key.
+// @Tags         kv
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        id    path  string             true  "Key ID"
+// @Param        body  body  v1.restoreRequest  true  "Version to restore"
+// @Success      200 {object} map[string]interface{} "Restored value and its new version"
+// @Failure      400 {object} map[string]interface{} "Invalid request or schema violation"
+// @Failure      404 {object} map[string]interface{} "Version not found"
+// @Failure      409 {object} map[string]interface{} "History is not enabled"
+// @Failure      410 {object} map[string]interface{} "Key is deleted in this version"
+// @Failure      415 {object} map[string]interface{} "Unsupported media type"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{id}/restore [post]
+func (hh HistoryHandler) Restore(c *gin.Context) {
+	key := c.Param("id")
+
+	var rq restoreRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+	if rq.Version == 0 {
+		respond(c, http.StatusBadRequest, gin.H{"error": "missing version"})
+		return
+	}
+
+	version, err := hh.Handler.Restore(c.Request.Context(), key, rq.Version)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			respond(c, http.StatusBadRequest, validationResponse(validationErr))
+		case errors.Is(err, repository.ErrVersionNotFound):
+			respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrHistoryDisabled):
+			respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrVersionDeleted):
+			respond(c, http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			requestLogger(c, hh.Logger).Warn("Tarantool failed to restore version",
+				"key", key,
+				"version", rq.Version,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"message":  "restored",
+		"key":      version.Key,
+		"value":    version.Value,
+		"version":  version.Version,
+		"restored": rq.Version,
+	})
+}
@@ -0,0 +1,99 @@
+// Routing of path-like keys under /kv.
+
+package v1
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"tarantool-app/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keyRoute serves one method of /kv/*path, or of another prefix of the
+// default collection. The escaped path is split on
+// slashes before its segments are unescaped, so an escaped slash (%2F)
+// stays inside a segment:
+//
+//	/kv/tenant/orders/123        key "tenant/orders/123"
+//	/kv/tenant/orders/123/blob   blob of key "tenant/orders/123"
+//	/kv/tenant/orders%2Fblob     key "tenant/orders/blob"
+//	/kv/tenant/orders/           children of path "tenant/orders"
+//	/kv/                         children of the root
+//
+// A subresource name ends the path only after a key, so /kv/blob is the
+// key "blob"; domain.ValidateKey keeps longer keys from ending with one.
+//
+// The key is resolved and passed to the handler as the "id" parameter;
+// paths of children are normalized.
+type keyRoute struct {
+	prefix       string // of the path, e.g. "/kv/"
+	resolve      func(c *gin.Context, key string) (string, error)
+	key          gin.HandlerFunc            // the key itself
+	children     gin.HandlerFunc            // a path ending with a slash
+	subresources map[string]gin.HandlerFunc // named by the last segment
+	reserved     map[string]gin.HandlerFunc // a single segment, e.g. _query
+}
+
+func (kr keyRoute) handle(c *gin.Context) {
+	segments, trailing, err := splitPath(strings.TrimPrefix(c.Request.URL.EscapedPath(), kr.prefix))
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid escape in path"})
+		return
+	}
+
+	handler := kr.key
+	switch n := len(segments); {
+	case trailing:
+		handler = kr.children
+	case n == 1 && kr.reserved[segments[0]] != nil:
+		kr.reserved[segments[0]](c)
+		return
+	case n > 1 && kr.subresources[segments[n-1]] != nil:
+		handler, segments = kr.subresources[segments[n-1]], segments[:n-1]
+	}
+	if handler == nil {
+		respond(c, http.StatusNotFound, gin.H{"error": "404 page not found"})
+		return
+	}
+
+	key := strings.Join(segments, domain.KeySeparator)
+	if trailing {
+		key = domain.NormalizeKey(key)
+	} else if key, err = kr.resolve(c, key); err != nil {
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		return
+	}
+
+	c.AddParam("id", key)
+	handler(c)
+}
+
+// splitPath splits an escaped path on slashes and unescapes its segments.
+// An empty path or one ending with a slash is trailing.
+func splitPath(escaped string) (segments []string, trailing bool, err error) {
+	trailing = escaped == "" || strings.HasSuffix(escaped, domain.KeySeparator)
+	escaped = strings.TrimSuffix(escaped, domain.KeySeparator)
+	if escaped == "" {
+		return nil, trailing, nil
+	}
+
+	for _, raw := range strings.Split(escaped, domain.KeySeparator) {
+		segment, err := url.PathUnescape(raw)
+		if err != nil {
+			return nil, trailing, err
+		}
+		segments = append(segments, segment)
+	}
+	return segments, trailing, nil
+}
+
+// joinPath is the inverse of splitPath for a path that is not trailing.
+func joinPath(segments []string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(escaped, domain.KeySeparator)
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"tarantool-app/internal/domain"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		escaped  string
+		segments []string
+		trailing bool
+		err      bool
+	}{
+		{"root", "", nil, true, false},
+		{"root with slash", "/", nil, true, false},
+		{"single segment", "orders", []string{"orders"}, false, false},
+		{"path", "tenant/orders/123", []string{"tenant", "orders", "123"}, false, false},
+		{"children", "tenant/orders/", []string{"tenant", "orders"}, true, false},
+		{"escaped slash", "tenant/orders%2Fblob", []string{"tenant", "orders/blob"}, false, false},
+		{"escaped space", "a/b%20c", []string{"a", "b c"}, false, false},
+		{"empty segment", "a//b", []string{"a", "", "b"}, false, false},
+		{"invalid escape", "a/%zz", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, trailing, err := splitPath(tt.escaped)
+			if (err != nil) != tt.err {
+				t.Fatalf("splitPath(%q) error %v, want error %v", tt.escaped, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(segments, tt.segments) || trailing != tt.trailing {
+				t.Fatalf("splitPath(%q) = %q, %v, want %q, %v", tt.escaped, segments, trailing, tt.segments, tt.trailing)
+			}
+		})
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		escaped  string
+	}{
+		{"single segment", []string{"orders"}, "orders"},
+		{"path", []string{"tenant", "orders", "123"}, "tenant/orders/123"},
+		{"slash in a segment", []string{"tenant", "orders/blob"}, "tenant/orders%2Fblob"},
+		{"space and percent", []string{"b c", "100%"}, "b%20c/100%25"},
+		{"unicode", []string{"café"}, "caf%C3%A9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escaped := joinPath(tt.segments)
+			if escaped != tt.escaped {
+				t.Fatalf("joinPath(%q) = %q, want %q", tt.segments, escaped, tt.escaped)
+			}
+			segments, trailing, err := splitPath(escaped)
+			if err != nil || trailing || !reflect.DeepEqual(segments, tt.segments) {
+				t.Fatalf("splitPath(%q) = %q, %v, %v, want %q back", escaped, segments, trailing, err, tt.segments)
+			}
+		})
+	}
+}
+
+func TestKeyRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The decomposed "cafe\u0301" was stored before keys were normalized.
+	stored := map[string]bool{"cafe\u0301": true}
+	route := keyRoute{
+		resolve: func(_ *gin.Context, key string) (string, error) {
+			if stored[key] {
+				return key, nil
+			}
+			return domain.NormalizeKey(key), nil
+		},
+		key:          echo("key"),
+		children:     echo("children"),
+		subresources: map[string]gin.HandlerFunc{"blob": echo("blob")},
+		reserved:     map[string]gin.HandlerFunc{"_query": echo("query")},
+	}
+
+	tests := []struct {
+		path string // below the prefix
+		want string
+	}{
+		{"", "children "},
+		{"tenant/orders/", "children tenant/orders"},
+		{"tenant/orders/123", "key tenant/orders/123"},
+		{"tenant/orders/123/blob", "blob tenant/orders/123"},
+		{"tenant/orders%2Fblob", "key tenant/orders/blob"},
+		{"blob", "key blob"},
+		{"_query", "query "},
+		{"me%CC%81nu", "key m\u00e9nu"},
+		{"cafe%CC%81", "key cafe\u0301"},
+		{"cafe%CC%81/blob", "blob cafe\u0301"},
+		{"caf%C3%A9", "key caf\u00e9"},
+	}
+
+	for _, prefix := range []string{"/kv/", "/collections/kv/"} {
+		route.prefix = prefix
+		for _, tt := range tests {
+			t.Run(prefix+tt.path, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodGet, prefix+tt.path, nil)
+				route.handle(c)
+				if got := w.Body.String(); got != tt.want {
+					t.Fatalf("GET %s%s served %q, want %q", prefix, tt.path, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+// echo answers with its name and the key the route resolved.
+func echo(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, name+" "+c.Param("id"))
+	}
+}
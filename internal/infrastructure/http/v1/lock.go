@@ -0,0 +1,184 @@
+// Handlers for distributed locks.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LockHandler struct {
+	Handler interfaces.LockUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.LockHandler = LockHandler{} // LockHandler must satisfy interfaces.LockHandler
+
+func NewLockHandler(uc interfaces.LockUseCase, log interfaces.Logger) LockHandler {
+	return LockHandler{Handler: uc, Logger: log}
+}
+
+// lockRequest is the body of POST and PUT /locks/:name. TTL is a Go
+// duration such as "30s"; the configured default is used when empty.
+type lockRequest struct {
+	Owner string `json:"owner"`
+	Token uint64 `json:"token"`
+	TTL   string `json:"ttl"`
+}
+
+func (rq lockRequest) lease() (time.Duration, error) {
+	if rq.TTL == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(rq.TTL)
+}
+
+// @Summary      Acquire a lock
+// @Description  Takes a lease on the lock for the owner and returns a fencing token. Acquiring a lock already held by the same owner extends the lease.
+// @Tags         locks
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name  path  string          true  "Lock name"
+// @Param        body  body  v1.lockRequest  true  "Owner and lease TTL"
+// @Success      200 {object} map[string]interface{} "Lock acquired"
+// @Failure      400 {object} map[string]interface{} "Invalid request or reserved name"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      409 {object} map[string]interface{} "Lock is held by another owner"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /locks/{name} [post]
+func (lh LockHandler) AcquireLock(c *gin.Context) {
+	name, ok := lockName(c)
+	if !ok {
+		return
+	}
+
+	var rq lockRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	ttl, err := rq.lease()
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid ttl"})
+		return
+	}
+
+	lock, err := lh.Handler.Acquire(c.Request.Context(), name, rq.Owner, ttl)
+	lh.respondLock(c, "acquire", lock, err)
+}
+
+// @Summary      Renew a lock
+// @Description  Extends the lease of a lock still held by the owner with the given fencing token.
+// @Tags         locks
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name  path  string          true  "Lock name"
+// @Param        body  body  v1.lockRequest  true  "Owner, fencing token and lease TTL"
+// @Success      200 {object} map[string]interface{} "Lease extended"
+// @Failure      400 {object} map[string]interface{} "Invalid request or reserved name"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      409 {object} map[string]interface{} "Lock is not held"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /locks/{name} [put]
+func (lh LockHandler) RenewLock(c *gin.Context) {
+	name, ok := lockName(c)
+	if !ok {
+		return
+	}
+
+	var rq lockRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	ttl, err := rq.lease()
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid ttl"})
+		return
+	}
+
+	lock, err := lh.Handler.Renew(c.Request.Context(), name, rq.Owner, rq.Token, ttl)
+	lh.respondLock(c, "renew", lock, err)
+}
+
+// @Summary      Release a lock
+// @Description  Releases a lock still held by the owner with the given fencing token.
+// @Tags         locks
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name   path   string  true  "Lock name"
+// @Param        owner  query  string  true  "Lock owner"
+// @Param        token  query  int     true  "Fencing token"
+// @Success      200 {object} map[string]interface{} "Lock released"
+// @Failure      400 {object} map[string]interface{} "Invalid request or reserved name"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      409 {object} map[string]interface{} "Lock is not held"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /locks/{name} [delete]
+func (lh LockHandler) ReleaseLock(c *gin.Context) {
+	name, ok := lockName(c)
+	if !ok {
+		return
+	}
+
+	token, err := strconv.ParseUint(c.Query("token"), 10, 64)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid token"})
+		return
+	}
+
+	lock, err := lh.Handler.Release(c.Request.Context(), name, c.Query("owner"), token)
+	lh.respondLock(c, "release", lock, err)
+}
+
+// Lock names starting with reservedLockPrefix are taken by the application
+// itself, e.g. for the leader election, and cannot be used over HTTP.
+const reservedLockPrefix = "_"
+
+// lockName returns the name of the lock, answering 400 for reserved names.
+func lockName(c *gin.Context) (string, bool) {
+	name := c.Param("name")
+	if strings.HasPrefix(name, reservedLockPrefix) {
+		respond(c, http.StatusBadRequest, gin.H{"error": "400 lock names starting with " + reservedLockPrefix + " are reserved"})
+		return "", false
+	}
+	return name, true
+}
+
+func (lh LockHandler) respondLock(c *gin.Context, action string, lock domain.Lock, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidLock):
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrLockHeld), errors.Is(err, repository.ErrLockNotHeld):
+			respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			requestLogger(c, lh.Logger).Warn("Tarantool failed to "+action+" lock",
+				"name", c.Param("name"),
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"name":       lock.Name,
+		"owner":      lock.Owner,
+		"token":      lock.Token,
+		"expires_at": lock.Expires().UTC().Format(time.RFC3339Nano),
+	})
+}
@@ -0,0 +1,91 @@
+// Request IDs, request-scoped loggers and the access log.
+
+package v1
+
+import (
+	"net/http"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	maxRequestIDLen = 128
+)
+
+// requestLogging tags the request with an ID, taken from X-Request-ID when
+// the client sent a usable one, and echoes it in the response. Every entry
+// of the request-scoped logger carries the ID, which is logged again with
+// the outcome of the request once it is served.
+func requestLogging(log interfaces.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(requestIDHeader, id)
+
+		reqLog := log.With("request_id", id)
+		c.Request = c.Request.WithContext(utils.ContextWithLogger(c.Request.Context(), reqLog))
+
+		c.Next()
+
+		fields := []any{
+			"method", c.Request.Method,
+			"path", redactedPath(c),
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"bytes", c.Writer.Size(),
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "errors", c.Errors.String())
+		}
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			reqLog.Warn("HTTP request", fields...)
+		} else {
+			reqLog.Info("HTTP request", fields...)
+		}
+	}
+}
+
+// validRequestID accepts short printable ASCII IDs only, so that client
+// input cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// recovery turns panics into 500 responses logged with the request ID.
+func recovery(log interfaces.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		requestLogger(c, log).Error("Panic while serving request",
+			"error", err,
+		)
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		c.Abort()
+	})
+}
+
+// requestLogger returns the logger of the request, fallback outside of
+// requestLogging.
+func requestLogger(c *gin.Context, fallback interfaces.Logger) interfaces.Logger {
+	return utils.LoggerFromContext(c.Request.Context(), fallback)
+}
@@ -0,0 +1,75 @@
+// Handlers for the runtime log level.
+
+package v1
+
+import (
+	"net/http"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LogHandler struct {
+	Handler interfaces.LogLevel
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.LogHandler = LogHandler{} // LogHandler must satisfy interfaces.LogHandler
+
+func NewLogHandler(level interfaces.LogLevel, log interfaces.Logger) LogHandler {
+	return LogHandler{Handler: level, Logger: log}
+}
+
+// logLevelRequest is the body of PUT /admin/log-level.
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// @Summary      Get the log level
+// @Description  Returns the level currently logged at.
+// @Tags         admin
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Success      200 {object} map[string]interface{} "Current level"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /admin/log-level [get]
+func (lh LogHandler) GetLogLevel(c *gin.Context) {
+	respond(c, http.StatusOK, gin.H{"level": lh.Handler.Level()})
+}
+
+// @Summary      Change the log level
+// @Description  Changes the level until the process restarts or app.log_level changes in the config. One of debug, info, warn, error.
+// @Tags         admin
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        body  body  v1.logLevelRequest  true  "New level"
+// @Success      200 {object} map[string]interface{} "Level applied"
+// @Failure      400 {object} map[string]interface{} "Unknown level"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /admin/log-level [put]
+func (lh LogHandler) PutLogLevel(c *gin.Context) {
+	var rq logLevelRequest
+
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if rq.Level == "" {
+		respond(c, http.StatusBadRequest, gin.H{"error": "400 level is required"})
+		return
+	}
+
+	previous := lh.Handler.Level()
+	if err := lh.Handler.SetLevel(rq.Level); err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "400 " + err.Error()})
+		return
+	}
+
+	requestLogger(c, lh.Logger).Info("Log level changed",
+		"from", previous,
+		"to", lh.Handler.Level(),
+	)
+	respond(c, http.StatusOK, gin.H{"level": lh.Handler.Level()})
+}
@@ -0,0 +1,119 @@
+// Content negotiation between JSON, MessagePack and CBOR encodings.
+
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	mimeJSON       = "application/json"
+	mimeMsgpack    = "application/msgpack"
+	mimeMsgpackAlt = "application/x-msgpack"
+	mimeCBOR       = "application/cbor"
+	mimeNDJSON     = "application/x-ndjson"
+	structTag      = "json" // msgpack and cbor reuse json struct tags
+)
+
+// Offered response formats, JSON being the default for clients without Accept.
+var offeredFormats = []string{mimeJSON, mimeMsgpack, mimeMsgpackAlt, mimeCBOR}
+
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+var (
+	cborDecMode = mustCBORDecMode()
+	cborEncMode = mustCBOREncMode()
+)
+
+func mustCBORDecMode() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+func mustCBOREncMode() cbor.EncMode {
+	mode, err := cbor.EncOptions{Sort: cbor.SortCanonical}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+// bind decodes request body into obj according to the Content-Type header.
+// Requests without Content-Type are treated as JSON.
+func bind(c *gin.Context, obj any) error {
+	switch c.ContentType() {
+	case "", mimeJSON:
+		return c.ShouldBindJSON(obj)
+	case mimeMsgpack, mimeMsgpackAlt:
+		dec := msgpack.NewDecoder(c.Request.Body)
+		dec.SetCustomStructTag(structTag)
+		return dec.Decode(obj)
+	case mimeCBOR:
+		return cborDecMode.NewDecoder(c.Request.Body).Decode(obj)
+	default:
+		return errUnsupportedMediaType
+	}
+}
+
+// bindError writes a 400 or 415 response for an error returned by bind.
+func bindError(c *gin.Context, err error) {
+	if errors.Is(err, errUnsupportedMediaType) {
+		respond(c, http.StatusUnsupportedMediaType, gin.H{"error": "415 unsupported media type"})
+		return
+	}
+	respond(c, http.StatusBadRequest, gin.H{"error": "invalid request body format"})
+}
+
+// respond serializes obj in the format preferred by the Accept header,
+// with its error details redacted.
+func respond(c *gin.Context, code int, obj any) {
+	obj = redactErrors(c, obj)
+	switch format := c.NegotiateFormat(offeredFormats...); format {
+	case mimeMsgpack, mimeMsgpackAlt:
+		data, err := marshalMsgpack(obj)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(code, format, data)
+	case mimeCBOR:
+		data, err := cborEncMode.Marshal(obj)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(code, mimeCBOR, data)
+	default:
+		c.JSON(code, obj)
+	}
+}
+
+// wantsNDJSON reports whether the client prefers newline-delimited JSON
+// over the formats accepted by respond.
+func wantsNDJSON(c *gin.Context) bool {
+	return c.NegotiateFormat(append(offeredFormats, mimeNDJSON)...) == mimeNDJSON
+}
+
+func marshalMsgpack(obj any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag(structTag)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(obj); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+// Handlers for queries over secondary indexes.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueryHandler struct {
+	Handler interfaces.QueryUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.QueryHandler = QueryHandler{} // QueryHandler must satisfy interfaces.QueryHandler
+
+func NewQueryHandler(uc interfaces.QueryUseCase, log interfaces.Logger) QueryHandler {
+	return QueryHandler{Handler: uc, Logger: log}
+}
+
+// @Summary      Query values by an indexed field
+// @Description  Selects values whose indexed field equals the given value, one page at a time.
+// @Tags         kv
+// @Produce      json,application/msgpack,application/cbor
+// @Param        field   query  string  true   "Indexed field, e.g. value.status"
+// @Param        eq      query  string  true   "Value to match"
+// @Param        limit   query  int     false  "Page size, 50 by default"
+// @Param        cursor  query  string  false  "Cursor returned by the previous page"
+// @Success      200 {object} map[string]interface{} "Matching values and the next cursor"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/_query [get]
+func (qh QueryHandler) QueryKV(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	page, err := qh.Handler.Query(c.Request.Context(), c.Query("field"), c.Query("eq"), limit, c.Query("cursor"))
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidQuery) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, qh.Logger).Warn("Tarantool failed to query values",
+				"field", c.Query("field"),
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	items := make([]gin.H, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, gin.H{"key": item.Key, "value": item.Value})
+	}
+
+	response := gin.H{"items": items}
+	if len(page.Next) > 0 {
+		response["cursor"] = usecases.EncodeCursor(page.Next)
+	}
+	respond(c, http.StatusOK, response)
+}
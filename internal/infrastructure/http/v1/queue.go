@@ -0,0 +1,301 @@
+// Handlers for work queues.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueHandler struct {
+	Handler interfaces.QueueUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.QueueHandler = QueueHandler{} // QueueHandler must satisfy interfaces.QueueHandler
+
+func NewQueueHandler(uc interfaces.QueueUseCase, log interfaces.Logger) QueueHandler {
+	return QueueHandler{Handler: uc, Logger: log}
+}
+
+// putTaskRequest is the body of POST /queues/:name. Delay is a Go duration
+// such as "10s".
+type putTaskRequest struct {
+	Payload any    `json:"payload"`
+	Delay   string `json:"delay"`
+}
+
+// @Summary      Put a task
+// @Description  Adds a task to the queue, visible to takers after the optional delay.
+// @Tags         queues
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name  path  string             true  "Queue name"
+// @Param        body  body  v1.putTaskRequest  true  "Payload and delay"
+// @Success      201 {object} map[string]interface{} "Task queued"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name} [post]
+func (qh QueueHandler) PutTask(c *gin.Context) {
+	var rq putTaskRequest
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	delay, ok := durationParam(c, "delay", rq.Delay)
+	if !ok {
+		return
+	}
+
+	task, err := qh.Handler.Put(c.Request.Context(), c.Param("name"), rq.Payload, delay)
+	if err != nil {
+		qh.taskError(c, "put", err)
+		return
+	}
+
+	respond(c, http.StatusCreated, taskResponse(task))
+}
+
+// @Summary      Peek at tasks
+// @Description  Lists tasks with the given status in the order they become due, without taking them. Buried tasks are the dead letters of the queue.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name    path   string  true   "Queue name"
+// @Param        status  query  string  false  "ready (default), taken or buried"
+// @Param        limit   query  int     false  "Number of tasks, 10 by default"
+// @Success      200 {object} map[string]interface{} "Tasks"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name} [get]
+func (qh QueueHandler) PeekTasks(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	tasks, err := qh.Handler.Peek(c.Request.Context(), c.Param("name"), c.Query("status"), limit)
+	if err != nil {
+		qh.taskError(c, "peek", err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, taskResponse(task))
+	}
+	respond(c, http.StatusOK, gin.H{"tasks": items})
+}
+
+// @Summary      Take a task
+// @Description  Takes the next due task and hides it for the visibility timeout. Waits up to `wait` for a task to appear (long polling). The receipt of the task is required to acknowledge, reject or bury it.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name        path   string  true   "Queue name"
+// @Param        wait        query  string  false  "Long-polling timeout, e.g. 20s"
+// @Param        visibility  query  string  false  "Visibility timeout, e.g. 1m"
+// @Success      200 {object} map[string]interface{} "Taken task"
+// @Success      204 "No task is due"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name}/take [post]
+func (qh QueueHandler) TakeTask(c *gin.Context) {
+	wait, ok := durationParam(c, "wait", c.Query("wait"))
+	if !ok {
+		return
+	}
+	visibility, ok := durationParam(c, "visibility", c.Query("visibility"))
+	if !ok {
+		return
+	}
+
+	task, err := qh.Handler.Take(c.Request.Context(), c.Param("name"), visibility, wait)
+	if err != nil {
+		if errors.Is(err, repository.ErrQueueEmpty) {
+			c.Status(http.StatusNoContent)
+		} else {
+			qh.taskError(c, "take", err)
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, taskResponse(task))
+}
+
+// @Summary      Acknowledge a task
+// @Description  Removes a taken task once it was processed.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name  path  string  true  "Queue name"
+// @Param        id       path   int     true  "Task ID"
+// @Param        receipt  query  int     true  "Receipt returned by the take"
+// @Success      200 {object} map[string]interface{} "Task removed"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      404 {object} map[string]interface{} "Task not found"
+// @Failure      409 {object} map[string]interface{} "Task is not taken or was taken again"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name}/tasks/{id}/ack [post]
+func (qh QueueHandler) AckTask(c *gin.Context) {
+	id, receipt, ok := takenTask(c)
+	if !ok {
+		return
+	}
+
+	task, err := qh.Handler.Ack(c.Request.Context(), c.Param("name"), id, receipt)
+	if err != nil {
+		qh.taskError(c, "ack", err)
+		return
+	}
+
+	respond(c, http.StatusOK, taskResponse(task))
+}
+
+// @Summary      Reject a task
+// @Description  Returns a taken task to the queue after the optional delay, or buries it once it used up its attempts.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name   path   string  true   "Queue name"
+// @Param        id       path   int     true   "Task ID"
+// @Param        receipt  query  int     true   "Receipt returned by the take"
+// @Param        delay    query  string  false  "Redelivery delay, e.g. 30s"
+// @Success      200 {object} map[string]interface{} "Task returned or buried"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      404 {object} map[string]interface{} "Task not found"
+// @Failure      409 {object} map[string]interface{} "Task is not taken or was taken again"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name}/tasks/{id}/nack [post]
+func (qh QueueHandler) NackTask(c *gin.Context) {
+	id, receipt, ok := takenTask(c)
+	if !ok {
+		return
+	}
+	delay, ok := durationParam(c, "delay", c.Query("delay"))
+	if !ok {
+		return
+	}
+
+	task, err := qh.Handler.Nack(c.Request.Context(), c.Param("name"), id, receipt, delay)
+	if err != nil {
+		qh.taskError(c, "nack", err)
+		return
+	}
+
+	respond(c, http.StatusOK, taskResponse(task))
+}
+
+// @Summary      Bury a task
+// @Description  Moves a taken task to the dead letters of the queue.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Security     ServiceToken
+// @Param        name  path  string  true  "Queue name"
+// @Param        id       path   int     true  "Task ID"
+// @Param        receipt  query  int     true  "Receipt returned by the take"
+// @Success      200 {object} map[string]interface{} "Task buried"
+// @Failure      400 {object} map[string]interface{} "Invalid request"
+// @Failure      404 {object} map[string]interface{} "Task not found"
+// @Failure      409 {object} map[string]interface{} "Task is not taken or was taken again"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /queues/{name}/tasks/{id}/bury [post]
+func (qh QueueHandler) BuryTask(c *gin.Context) {
+	id, receipt, ok := takenTask(c)
+	if !ok {
+		return
+	}
+
+	task, err := qh.Handler.Bury(c.Request.Context(), c.Param("name"), id, receipt)
+	if err != nil {
+		qh.taskError(c, "bury", err)
+		return
+	}
+
+	respond(c, http.StatusOK, taskResponse(task))
+}
+
+func (qh QueueHandler) taskError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTask):
+		respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrTaskNotFound):
+		respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrTaskNotTaken), errors.Is(err, repository.ErrTaskTakenAgain):
+		respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		requestLogger(c, qh.Logger).Warn("Tarantool failed to "+action+" task",
+			"queue", c.Param("name"),
+			"error", err,
+		)
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+	}
+}
+
+// takenTask returns the task ID and the receipt of its take, responding
+// with 400 if either is malformed.
+func takenTask(c *gin.Context) (uint64, uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return 0, 0, false
+	}
+	receipt, err := strconv.ParseUint(c.Query("receipt"), 10, 64)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid receipt"})
+		return 0, 0, false
+	}
+	return id, receipt, true
+}
+
+// durationParam parses an optional duration, responding with 400 if it is
+// malformed.
+func durationParam(c *gin.Context, name, raw string) (time.Duration, bool) {
+	if raw == "" {
+		return 0, true
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		respond(c, http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return d, true
+}
+
+// taskResponse renders the task. Taken tasks carry the receipt of their
+// latest take.
+func taskResponse(task domain.Task) gin.H {
+	response := gin.H{
+		"id":         task.ID,
+		"queue":      task.Queue,
+		"status":     task.Status,
+		"ready_at":   task.Ready().UTC().Format(time.RFC3339Nano),
+		"attempts":   task.Attempts,
+		"created_at": task.Created().UTC().Format(time.RFC3339Nano),
+		"payload":    task.Payload,
+	}
+	if task.Status == domain.TaskTaken {
+		response["receipt"] = task.Receipt()
+	}
+	return response
+}
@@ -0,0 +1,96 @@
+// Redaction of keys and error details leaving the handlers.
+
+package v1
+
+import (
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const redactorKey = "redactor"
+
+// keyParams name the route parameters holding keys of the store.
+var keyParams = []string{"id", "key"}
+
+// redaction makes the redaction policy available to respond and to the
+// access log of the request.
+func redaction(r *utils.Redactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(redactorKey, r)
+		c.Next()
+	}
+}
+
+// requestRedactor returns the redactor of the request, nil, which redacts
+// nothing, outside of redaction.
+func requestRedactor(c *gin.Context) *utils.Redactor {
+	r, _ := c.Value(redactorKey).(*utils.Redactor)
+	return r
+}
+
+// redactedPath returns the request path with the keys it contains
+// redacted.
+func redactedPath(c *gin.Context) string {
+	r := requestRedactor(c)
+	path := c.Request.URL.Path
+	for _, name := range keyParams {
+		if key := c.Param(name); key != "" {
+			path = strings.Replace(path, key, r.Key(key), 1)
+		}
+	}
+	return path
+}
+
+// redactErrors truncates the error details of responses, which may quote
+// stored data, e.g. tuples in Tarantool errors or values rejected by a
+// schema. Other responses are returned as is.
+func redactErrors(c *gin.Context, obj any) any {
+	r := requestRedactor(c)
+	if r == nil {
+		return obj
+	}
+	if report, ok := obj.(domain.ImportReport); ok {
+		return redactReport(r, report)
+	}
+	body, ok := obj.(gin.H)
+	if !ok {
+		return obj
+	}
+
+	redacted := make(gin.H, len(body))
+	for name, value := range body {
+		switch v := value.(type) {
+		case string:
+			if name == "error" {
+				value = r.Message(v)
+			}
+		case []domain.Violation:
+			violations := make([]domain.Violation, len(v))
+			for i, violation := range v {
+				violations[i] = domain.Violation{Path: violation.Path, Message: r.Message(violation.Message)}
+			}
+			value = violations
+		case domain.ImportReport:
+			value = redactReport(r, v)
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// redactReport redacts the keys and reasons of failed imports.
+func redactReport(r *utils.Redactor, report domain.ImportReport) domain.ImportReport {
+	if len(report.Failures) == 0 {
+		return report
+	}
+
+	failures := make([]domain.ImportFailure, len(report.Failures))
+	for i, failure := range report.Failures {
+		failures[i] = domain.ImportFailure{Key: r.Key(failure.Key), Error: r.Message(failure.Error)}
+	}
+	report.Failures = failures
+	return report
+}
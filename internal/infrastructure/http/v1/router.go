@@ -1,7 +1,11 @@
 package v1
 
 import (
+	"expvar"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
 	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,12 +14,31 @@ type GinRouter struct {
 	Engine *gin.Engine
 }
 
-func NewGinRouter(env string, log interfaces.Logger, h interfaces.KVHandler) *GinRouter {
-	if env == "production" {
+// Handlers groups the handlers of every API subsystem.
+type Handlers struct {
+	KV         interfaces.KVHandler
+	Blob       interfaces.BlobHandler
+	Schema     interfaces.SchemaHandler
+	Query      interfaces.QueryHandler
+	SQL        interfaces.SQLHandler
+	Counter    interfaces.CounterHandler
+	Lock       interfaces.LockHandler
+	Queue      interfaces.QueueHandler
+	Transfer   interfaces.TransferHandler
+	Log        interfaces.LogHandler
+	Audit      interfaces.AuditHandler
+	History    interfaces.HistoryHandler
+	Tree       interfaces.TreeHandler
+	Collection interfaces.CollectionHandler
+}
+
+func NewGinRouter(cfg config.Config, log interfaces.Logger, h Handlers) *GinRouter {
+	if cfg.App.Production() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	r := gin.New()
+	r.Use(redaction(utils.NewRedactor(cfg.Redaction)), requestLogging(log), recovery(log), auditActor(cfg.Auth.AdminToken))
 
 	if err := r.SetTrustedProxies(nil); err != nil {
 		log.Debug("Error setting SetTrustedProxies to nil",
@@ -23,7 +46,7 @@ func NewGinRouter(env string, log interfaces.Logger, h interfaces.KVHandler) *Gi
 		)
 	}
 
-	setupRoutes(r, h)
+	setupRoutes(r, cfg, h)
 
 	return &GinRouter{Engine: r}
 }
@@ -32,13 +55,108 @@ func (g *GinRouter) Run(addr string) error {
 	return g.Engine.Run(addr)
 }
 
-func setupRoutes(r *gin.Engine, h interfaces.KVHandler) {
+func setupRoutes(r *gin.Engine, cfg config.Config, h Handlers) {
 	// r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
-	appGroup := r.Group("/kv")
+
+	// /kv serves the default collection, also found under /collections. It is
+	// the only collection with the full set of KV routes, the others have
+	// item, scan and query routes only.
+	kvRoutes(r.Group("/kv"), h)
+
+	// Key parts are the segments of *key, a path ending with a slash scans
+	// the items with the leading parts.
+	collectionGroup := r.Group("/collections")
+	{
+		kvRoutes(collectionGroup.Group("/"+domain.DefaultCollection), h)
+		collectionGroup.POST("/:name/*key", h.Collection.PostItem)
+		collectionGroup.GET("/:name/*key", collectionRoute{
+			item:     h.Collection.GetItem,
+			scan:     h.Collection.ScanItems,
+			reserved: map[string]gin.HandlerFunc{"_query": h.Collection.QueryItems},
+		}.handle)
+		collectionGroup.PUT("/:name/*key", h.Collection.PutItem)
+		collectionGroup.DELETE("/:name/*key", h.Collection.DeleteItem)
+	}
+
+	lockGroup := r.Group("/locks", requireService(cfg.Auth.ServiceToken, cfg.Auth.AdminToken))
+	{
+		lockGroup.POST("/:name", h.Lock.AcquireLock)
+		lockGroup.PUT("/:name", h.Lock.RenewLock)
+		lockGroup.DELETE("/:name", h.Lock.ReleaseLock)
+	}
+
+	queueGroup := r.Group("/queues", requireService(cfg.Auth.ServiceToken, cfg.Auth.AdminToken))
+	{
+		queueGroup.POST("/:name", h.Queue.PutTask)
+		queueGroup.GET("/:name", h.Queue.PeekTasks)
+		queueGroup.POST("/:name/take", h.Queue.TakeTask)
+		queueGroup.POST("/:name/tasks/:id/ack", h.Queue.AckTask)
+		queueGroup.POST("/:name/tasks/:id/nack", h.Queue.NackTask)
+		queueGroup.POST("/:name/tasks/:id/bury", h.Queue.BuryTask)
+	}
+
+	adminGroup := r.Group("/admin", requireAdmin(cfg.Auth.AdminToken))
 	{
-		appGroup.POST("", h.PostKV)
-		appGroup.PUT("/:id", h.PutKV)
-		appGroup.GET("/:id", h.GetKV)
-		appGroup.DELETE("/:id", h.DeleteKV)
+		adminGroup.GET("/schemas", h.Schema.ListSchemas)
+		adminGroup.PUT("/schemas", h.Schema.PutSchema)
+		adminGroup.DELETE("/schemas", h.Schema.DeleteSchema)
+		adminGroup.POST("/sql", h.SQL.PostSQL)
+		adminGroup.GET("/export", h.Transfer.Export)
+		adminGroup.POST("/import", h.Transfer.Import)
+		adminGroup.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+		adminGroup.GET("/log-level", h.Log.GetLogLevel)
+		adminGroup.PUT("/log-level", h.Log.PutLogLevel)
+		adminGroup.GET("/audit", h.Audit.GetAudit)
+		adminGroup.GET("/audit/export", h.Audit.ExportAudit)
+		adminGroup.GET("/collections", h.Collection.ListCollections)
+		adminGroup.POST("/collections", h.Collection.CreateCollection)
+		adminGroup.DELETE("/collections/:name", h.Collection.DropCollection)
 	}
 }
+
+// kvRoutes adds the routes of the keys of the default collection to g.
+// Keys may contain slashes, so every route below g is dispatched by
+// keyRoute: /kv/:id, /kv/:id/blob, /kv/:path/ and so on.
+func kvRoutes(g *gin.RouterGroup, h Handlers) {
+	prefix := g.BasePath() + "/"
+
+	g.POST("", h.KV.PostKV)
+	g.GET("/*path", keyRoute{
+		prefix:   prefix,
+		resolve:  h.KV.ResolveKey,
+		key:      withVersion(h.KV.GetKV, h.History),
+		children: h.Tree.ListChildren,
+		reserved: map[string]gin.HandlerFunc{"_query": h.Query.QueryKV},
+		subresources: map[string]gin.HandlerFunc{
+			"blob":    h.Blob.GetBlob,
+			"history": h.History.GetHistory,
+		},
+	}.handle)
+	g.HEAD("/*path", keyRoute{
+		prefix:       prefix,
+		resolve:      h.KV.ResolveKey,
+		subresources: map[string]gin.HandlerFunc{"blob": h.Blob.GetBlob},
+	}.handle)
+	g.PUT("/*path", keyRoute{
+		prefix:       prefix,
+		resolve:      h.KV.ResolveKey,
+		key:          h.KV.PutKV,
+		subresources: map[string]gin.HandlerFunc{"blob": h.Blob.PutBlob},
+	}.handle)
+	g.POST("/*path", keyRoute{
+		prefix:  prefix,
+		resolve: h.KV.ResolveKey,
+		subresources: map[string]gin.HandlerFunc{
+			"incr":     h.Counter.Incr,
+			"decr":     h.Counter.Decr,
+			"restore":  h.History.Restore,
+			"undelete": h.KV.UndeleteKV,
+		},
+	}.handle)
+	g.DELETE("/*path", keyRoute{
+		prefix:       prefix,
+		resolve:      h.KV.ResolveKey,
+		key:          withRecursive(h.KV.DeleteKV, h.Tree),
+		subresources: map[string]gin.HandlerFunc{"blob": h.Blob.DeleteBlob},
+	}.handle)
+}
@@ -0,0 +1,142 @@
+// Handlers for JSON Schema administration.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchemaHandler struct {
+	Handler interfaces.SchemaUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.SchemaHandler = SchemaHandler{} // SchemaHandler must satisfy interfaces.SchemaHandler
+
+func NewSchemaHandler(uc interfaces.SchemaUseCase, log interfaces.Logger) SchemaHandler {
+	return SchemaHandler{Handler: uc, Logger: log}
+}
+
+// @Summary      List JSON schemas
+// @Description  Lists JSON schemas enforced per key prefix, from the config file and the admin API.
+// @Tags         admin
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Success      200 {object} map[string]interface{} "Registered schemas"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/schemas [get]
+func (sh SchemaHandler) ListSchemas(c *gin.Context) {
+	schemas, err := sh.Handler.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c, sh.Logger).Warn("Tarantool failed to list schemas",
+			"error", err,
+		)
+		respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"schemas": schemaResponses(schemas)})
+}
+
+// @Summary      Register a JSON schema
+// @Description  Registers or replaces the JSON schema enforced for values of keys with the prefix.
+// @Tags         admin
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        body  body  domain.Schema  true  "Key prefix and JSON schema"
+// @Success      200 {object} map[string]interface{} "Registered successfully"
+// @Failure      400 {object} map[string]interface{} "Invalid schema"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/schemas [put]
+func (sh SchemaHandler) PutSchema(c *gin.Context) {
+	var rq schemaRequest
+
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if len(rq.Schema) == 0 {
+		respond(c, http.StatusBadRequest, gin.H{"error": "missing schema"})
+		return
+	}
+
+	schema := domain.Schema{Prefix: rq.Prefix, Document: rq.Schema}
+	if err := sh.Handler.Register(c.Request.Context(), schema); err != nil {
+		if errors.Is(err, usecases.ErrInvalidSchema) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, sh.Logger).Warn("Tarantool failed to store schema",
+				"prefix", rq.Prefix,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"message": "registered",
+		"prefix":  schema.Prefix,
+		"schema":  schema.Document,
+	})
+}
+
+// @Summary      Unregister a JSON schema
+// @Description  Removes the JSON schema registered through the admin API for the prefix.
+// @Tags         admin
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        prefix  query  string  true  "Key prefix"
+// @Success      200 {object} map[string]interface{} "Unregistered successfully"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Schema not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/schemas [delete]
+func (sh SchemaHandler) DeleteSchema(c *gin.Context) {
+	prefix := c.Query("prefix")
+
+	schema, err := sh.Handler.Unregister(c.Request.Context(), prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(c, http.StatusNotFound, gin.H{"error": "404 schema not found"})
+		} else {
+			requestLogger(c, sh.Logger).Warn("Tarantool failed to delete schema",
+				"prefix", prefix,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"message": "unregistered",
+		"prefix":  schema.Prefix,
+		"schema":  schema.Document,
+	})
+}
+
+// schemaRequest is the body of PUT /admin/schemas.
+type schemaRequest struct {
+	Prefix string         `json:"prefix"`
+	Schema map[string]any `json:"schema"`
+}
+
+func schemaResponses(schemas []domain.Schema) []gin.H {
+	result := make([]gin.H, 0, len(schemas))
+	for _, s := range schemas {
+		result = append(result, gin.H{"prefix": s.Prefix, "schema": s.Document})
+	}
+	return result
+}
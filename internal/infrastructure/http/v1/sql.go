@@ -0,0 +1,107 @@
+// Handlers for read-only SQL queries.
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/repository"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SQLHandler struct {
+	Handler interfaces.SQLUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.SQLHandler = SQLHandler{} // SQLHandler must satisfy interfaces.SQLHandler
+
+func NewSQLHandler(uc interfaces.SQLUseCase, log interfaces.Logger) SQLHandler {
+	return SQLHandler{Handler: uc, Logger: log}
+}
+
+// sqlRequest is the body of POST /admin/sql.
+type sqlRequest struct {
+	Query  string `json:"query"`
+	Params []any  `json:"params"`
+	Limit  int    `json:"limit"`
+}
+
+// @Summary      Run a read-only SQL query
+// @Description  Runs a single SELECT statement with bound parameters. Rows are returned as JSON arrays, or as NDJSON objects when requested with Accept: application/x-ndjson.
+// @Tags         admin
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/x-ndjson,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        body  body  v1.sqlRequest  true  "Statement, positional parameters and row limit"
+// @Success      200 {object} map[string]interface{} "Column metadata and rows"
+// @Failure      400 {object} map[string]interface{} "Statement rejected or failed"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      504 {object} map[string]interface{} "Statement timed out"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/sql [post]
+func (sh SQLHandler) PostSQL(c *gin.Context) {
+	var rq sqlRequest
+
+	if err := bind(c, &rq); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	result, err := sh.Handler.Query(c.Request.Context(), rq.Query, rq.Params, rq.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrNotReadOnly), errors.Is(err, usecases.ErrTieredSpace), errors.Is(err, repository.ErrSQLStatementFail):
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, context.DeadlineExceeded):
+			respond(c, http.StatusGatewayTimeout, gin.H{"error": "504 statement timed out"})
+		default:
+			requestLogger(c, sh.Logger).Warn("Tarantool failed to execute SQL",
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if wantsNDJSON(c) {
+		writeSQLNDJSON(c, result)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"columns":   result.Columns,
+		"rows":      result.Rows,
+		"truncated": result.Truncated,
+	})
+}
+
+// writeSQLNDJSON writes the column metadata on the first line followed by
+// one object per row keyed by column name.
+func writeSQLNDJSON(c *gin.Context, result domain.SQLResult) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", mimeNDJSON)
+
+	enc := json.NewEncoder(c.Writer)
+	if err := enc.Encode(gin.H{"columns": result.Columns, "truncated": result.Truncated}); err != nil {
+		return
+	}
+
+	for _, row := range result.Rows {
+		object := make(map[string]any, len(row))
+		for i, value := range row {
+			if i < len(result.Columns) {
+				object[result.Columns[i].Name] = value
+			}
+		}
+		if err := enc.Encode(object); err != nil {
+			return
+		}
+	}
+}
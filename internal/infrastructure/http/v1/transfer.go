@@ -0,0 +1,153 @@
+// Handlers for bulk export and import.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Trailers of an export: the number of values written and whether the
+// export completed, since a failure after the status line only truncates
+// the body.
+const (
+	exportCountTrailer    = "X-Export-Count"
+	exportCompleteTrailer = "X-Export-Complete"
+)
+
+// Media types of the export formats.
+var transferMediaTypes = map[string]string{
+	domain.FormatNDJSON:  mimeNDJSON,
+	domain.FormatCSV:     "text/csv",
+	domain.FormatMsgpack: mimeMsgpack,
+}
+
+type TransferHandler struct {
+	Handler interfaces.TransferUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.TransferHandler = TransferHandler{} // TransferHandler must satisfy interfaces.TransferHandler
+
+func NewTransferHandler(uc interfaces.TransferUseCase, log interfaces.Logger) TransferHandler {
+	return TransferHandler{Handler: uc, Logger: log}
+}
+
+// @Summary      Export values
+// @Description  Streams every value whose key has the prefix as NDJSON, CSV or a stream of msgpack [key, value] arrays. The X-Export-Count and X-Export-Complete trailers report the number of values and whether the export completed.
+// @Tags         admin
+// @Produce      application/x-ndjson,text/csv,application/msgpack
+// @Security     AdminToken
+// @Param        prefix  query  string  false  "Key prefix"
+// @Param        format  query  string  false  "ndjson (default), csv or msgpack"
+// @Success      200 {file} binary "Exported values"
+// @Failure      400 {object} map[string]interface{} "Unknown format"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/export [get]
+func (th TransferHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", domain.FormatNDJSON)
+	mediaType, ok := transferMediaTypes[format]
+	if !ok {
+		respond(c, http.StatusBadRequest, gin.H{"error": "unknown format"})
+		return
+	}
+
+	c.Header("Content-Type", mediaType)
+	c.Header("Content-Disposition", `attachment; filename="export.`+format+`"`)
+	c.Header("Trailer", exportCountTrailer+", "+exportCompleteTrailer)
+	c.Status(http.StatusOK)
+
+	exported, err := th.Handler.Export(c.Request.Context(), c.Writer, c.Query("prefix"), format)
+	c.Header(exportCountTrailer, strconv.Itoa(exported))
+	c.Header(exportCompleteTrailer, strconv.FormatBool(err == nil))
+	if err != nil {
+		// The status line is already sent, the client sees a truncated body
+		// and X-Export-Complete: false.
+		requestLogger(c, th.Logger).Warn("Export failed",
+			"prefix", c.Query("prefix"),
+			"exported", exported,
+			"error", err,
+		)
+		c.Abort()
+	}
+}
+
+// @Summary      Import values
+// @Description  Reads values in the export format from the request body and writes them in batches. Existing keys are skipped, overwritten or stop the import depending on the policy.
+// @Tags         admin
+// @Accept       application/x-ndjson,text/csv,application/msgpack
+// @Produce      json,application/msgpack,application/cbor
+// @Security     AdminToken
+// @Param        format   query  string  false  "ndjson, csv or msgpack; derived from Content-Type by default"
+// @Param        policy   query  string  false  "skip (default), overwrite or fail"
+// @Param        batch    query  int     false  "Values written per batch, 500 by default"
+// @Param        dry_run  query  bool    false  "Report without writing"
+// @Success      200 {object} domain.ImportReport "Import report"
+// @Failure      400 {object} map[string]interface{} "Malformed input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      409 {object} map[string]interface{} "Existing key with the fail policy"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /admin/import [post]
+func (th TransferHandler) Import(c *gin.Context) {
+	opts := domain.ImportOptions{
+		Format: c.Query("format"),
+		Policy: c.Query("policy"),
+	}
+	if opts.Format == "" {
+		opts.Format = importFormat(c.ContentType())
+	}
+
+	if raw := c.Query("batch"); raw != "" {
+		var err error
+		if opts.BatchSize, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid batch"})
+			return
+		}
+	}
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+	}
+
+	report, err := th.Handler.Import(c.Request.Context(), c.Request.Body, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidTransfer):
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error(), "report": report})
+		case errors.Is(err, usecases.ErrImportConflict):
+			respond(c, http.StatusConflict, gin.H{"error": err.Error(), "report": report})
+		default:
+			requestLogger(c, th.Logger).Warn("Import failed",
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error", "report": report})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, report)
+}
+
+// importFormat picks the format matching the request media type, NDJSON
+// being the default.
+func importFormat(contentType string) string {
+	for format, mediaType := range transferMediaTypes {
+		if contentType == mediaType {
+			return format
+		}
+	}
+	if contentType == mimeMsgpackAlt {
+		return domain.FormatMsgpack
+	}
+	return domain.FormatNDJSON
+}
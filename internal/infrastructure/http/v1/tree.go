@@ -0,0 +1,112 @@
+// Handlers for hierarchical operations on path-like keys.
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TreeHandler struct {
+	Handler interfaces.TreeUseCase
+	Logger  interfaces.Logger
+}
+
+var _ interfaces.TreeHandler = TreeHandler{} // TreeHandler must satisfy interfaces.TreeHandler
+
+func NewTreeHandler(uc interfaces.TreeUseCase, log interfaces.Logger) TreeHandler {
+	return TreeHandler{Handler: uc, Logger: log}
+}
+
+// @Summary      List the children of a path
+// @Description  Lists the immediate children of a path, in key order, one page at a time. The path ends with a slash; /kv/ lists the top level. A child is a key, the parent of deeper keys, or both.
+// @Tags         kv
+// @Produce      json,application/msgpack,application/cbor
+// @Param        path   path   string  true   "Path, e.g. tenant/orders"
+// @Param        limit  query  int     false  "Page size, 100 by default"
+// @Param        after  query  string  false  "Name of the last child of the previous page"
+// @Success      200 {object} map[string]interface{} "Children and the name to continue after"
+// @Failure      400 {object} map[string]interface{} "Invalid query"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /kv/{path}/ [get]
+func (th TreeHandler) ListChildren(c *gin.Context) {
+	path := c.Param("id")
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			respond(c, http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	children, err := th.Handler.Children(c.Request.Context(), path, c.Query("after"), limit)
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidTree) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, th.Logger).Warn("Tarantool failed to list children",
+				"key", path,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error"})
+		}
+		return
+	}
+
+	if children == nil {
+		children = []domain.Child{}
+	}
+	response := gin.H{"path": path, "children": children}
+	if len(children) > 0 {
+		response["after"] = children[len(children)-1].Name
+	}
+	respond(c, http.StatusOK, response)
+}
+
+// DeleteTree answers DELETE /kv/:id?recursive=true, documented with
+// DeleteKV.
+func (th TreeHandler) DeleteTree(c *gin.Context) {
+	path := c.Param("id")
+
+	deleted, err := th.Handler.DeleteTree(c.Request.Context(), path)
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidTree) {
+			respond(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			requestLogger(c, th.Logger).Warn("Tarantool failed to delete subtree",
+				"key", path,
+				"deleted", deleted,
+				"error", err,
+			)
+			respond(c, http.StatusInternalServerError, gin.H{"error": "500 Internal server error", "deleted": deleted})
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"message": "deleted",
+		"path":    path,
+		"deleted": deleted,
+	})
+}
+
+// withRecursive routes DELETE /kv/:id to the deletion of the subtree when
+// the recursive query parameter is true, to the deletion of the key
+// otherwise.
+func withRecursive(current gin.HandlerFunc, th interfaces.TreeHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if recursive, _ := strconv.ParseBool(c.Query("recursive")); recursive {
+			th.DeleteTree(c)
+			return
+		}
+		current(c)
+	}
+}
@@ -3,8 +3,89 @@ package interfaces
 import "github.com/gin-gonic/gin"
 
 type KVHandler interface {
-	GetKV(c *gin.Context)    // GET /kv/:id
-	PostKV(c *gin.Context)   // POST /kv
-	PutKV(c *gin.Context)    // PUT /kv/:id
-	DeleteKV(c *gin.Context) // DELETE /kv/:id
+	GetKV(c *gin.Context)      // GET /kv/:id
+	PostKV(c *gin.Context)     // POST /kv
+	PutKV(c *gin.Context)      // PUT /kv/:id
+	DeleteKV(c *gin.Context)   // DELETE /kv/:id
+	UndeleteKV(c *gin.Context) // POST /kv/:id/undelete
+
+	ResolveKey(c *gin.Context, key string) (string, error) // the key of /kv/*path
+}
+
+type BlobHandler interface {
+	GetBlob(c *gin.Context)    // GET /kv/:id/blob
+	PutBlob(c *gin.Context)    // PUT /kv/:id/blob
+	DeleteBlob(c *gin.Context) // DELETE /kv/:id/blob
+}
+
+type SchemaHandler interface {
+	ListSchemas(c *gin.Context)  // GET /admin/schemas
+	PutSchema(c *gin.Context)    // PUT /admin/schemas
+	DeleteSchema(c *gin.Context) // DELETE /admin/schemas?prefix=
+}
+
+type QueryHandler interface {
+	QueryKV(c *gin.Context) // GET /kv/_query
+}
+
+type SQLHandler interface {
+	PostSQL(c *gin.Context) // POST /admin/sql
+}
+
+type CounterHandler interface {
+	Incr(c *gin.Context) // POST /kv/:id/incr
+	Decr(c *gin.Context) // POST /kv/:id/decr
+}
+
+type LockHandler interface {
+	AcquireLock(c *gin.Context) // POST /locks/:name
+	RenewLock(c *gin.Context)   // PUT /locks/:name
+	ReleaseLock(c *gin.Context) // DELETE /locks/:name
+}
+
+type QueueHandler interface {
+	PutTask(c *gin.Context)   // POST /queues/:name
+	PeekTasks(c *gin.Context) // GET /queues/:name
+	TakeTask(c *gin.Context)  // POST /queues/:name/take
+	AckTask(c *gin.Context)   // POST /queues/:name/tasks/:id/ack
+	NackTask(c *gin.Context)  // POST /queues/:name/tasks/:id/nack
+	BuryTask(c *gin.Context)  // POST /queues/:name/tasks/:id/bury
+}
+
+type TransferHandler interface {
+	Export(c *gin.Context) // GET /admin/export
+	Import(c *gin.Context) // POST /admin/import
+}
+
+type LogHandler interface {
+	GetLogLevel(c *gin.Context) // GET /admin/log-level
+	PutLogLevel(c *gin.Context) // PUT /admin/log-level
+}
+
+type AuditHandler interface {
+	GetAudit(c *gin.Context)    // GET /admin/audit
+	ExportAudit(c *gin.Context) // GET /admin/audit/export
+}
+
+type HistoryHandler interface {
+	GetHistory(c *gin.Context) // GET /kv/:id/history
+	GetVersion(c *gin.Context) // GET /kv/:id?version=
+	Restore(c *gin.Context)    // POST /kv/:id/restore
+}
+
+type TreeHandler interface {
+	ListChildren(c *gin.Context) // GET /kv/*path/
+	DeleteTree(c *gin.Context)   // DELETE /kv/*path?recursive=true
+}
+
+type CollectionHandler interface {
+	CreateCollection(c *gin.Context) // POST /admin/collections
+	ListCollections(c *gin.Context)  // GET /admin/collections
+	DropCollection(c *gin.Context)   // DELETE /admin/collections/:name
+	PostItem(c *gin.Context)         // POST /collections/:name/*key
+	GetItem(c *gin.Context)          // GET /collections/:name/*key
+	PutItem(c *gin.Context)          // PUT /collections/:name/*key
+	DeleteItem(c *gin.Context)       // DELETE /collections/:name/*key
+	ScanItems(c *gin.Context)        // GET /collections/:name/*prefix/
+	QueryItems(c *gin.Context)       // GET /collections/:name/_query
 }
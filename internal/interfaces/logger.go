@@ -6,5 +6,12 @@ type Logger interface {
 	Warn(msg string, keysAndValues ...any)
 	Error(msg string, keysAndValues ...any)
 	Fatal(msg string, keysAndValues ...any)
+	With(keysAndValues ...any) Logger
 	Sync()
 }
+
+// LogLevel changes the level of a logger and every logger derived from it.
+type LogLevel interface {
+	Level() string
+	SetLevel(level string) error
+}
@@ -1,11 +1,114 @@
 package interfaces
 
-import "tarantool-app/internal/domain"
+import (
+	"context"
+	"tarantool-app/internal/domain"
+	"time"
+)
 
 type Repository interface {
-	Insert(domain.Payload) error
-	Select(domain.Payload) (domain.Payload, error)
-	Update(domain.Payload) error
-	Delete(domain.Payload) (domain.Payload, error)
+	Insert(context.Context, domain.Payload) error
+	Select(context.Context, domain.Payload) (domain.Payload, error)
+	Update(context.Context, domain.Payload) error
+	Delete(context.Context, domain.Payload) (domain.Payload, error)
+	Undelete(context.Context, domain.Payload) (domain.Payload, error)
 	Close()
 }
+
+type BlobRepository interface {
+	BeginBlob(ctx context.Context, key, generation string, timeout time.Duration) error
+	InsertChunk(context.Context, domain.BlobChunk) error
+	SelectChunk(ctx context.Context, key, generation string, seq uint64) (domain.BlobChunk, error)
+	CommitBlob(ctx context.Context, meta domain.BlobMeta, retention time.Duration) (domain.BlobMeta, error)
+	AbortBlob(ctx context.Context, key, generation string) error
+	SelectBlob(ctx context.Context, key string) (domain.BlobMeta, error)
+	DeleteBlob(ctx context.Context, key string, retention time.Duration) (domain.BlobMeta, error)
+}
+
+type KeyRotationRepository interface {
+	ReloadKeyring(ctx context.Context) (bool, error)
+	RotateKeys(ctx context.Context, batchSize int) (int, error)
+}
+
+type TieringRepository interface {
+	DemoteIdle(ctx context.Context, idle time.Duration, after string, limit int) (int, string, error)
+}
+
+type SchemaRepository interface {
+	SelectSchemas(context.Context) ([]domain.Schema, error)
+	ReplaceSchema(context.Context, domain.Schema) error
+	DeleteSchema(ctx context.Context, prefix string) (domain.Schema, error)
+}
+
+type QueryRepository interface {
+	SelectByIndex(context.Context, domain.Query) (domain.Page, error)
+	Reindex(ctx context.Context, batchSize int) (int, error)
+}
+
+type SQLRepository interface {
+	Execute(context.Context, domain.SQLQuery) (domain.SQLResult, error)
+}
+
+type CounterRepository interface {
+	Increment(context.Context, domain.Increment) (any, error)
+}
+
+type LockRepository interface {
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (domain.Lock, error)
+	RenewLock(ctx context.Context, name, owner string, token uint64, ttl time.Duration) (domain.Lock, error)
+	ReleaseLock(ctx context.Context, name, owner string, token uint64) (domain.Lock, error)
+}
+
+type QueueRepository interface {
+	PutTask(ctx context.Context, queue string, payload any, delay time.Duration) (domain.Task, error)
+	TakeTask(ctx context.Context, queue string, visibility time.Duration, maxAttempts int, wait time.Duration) (domain.Task, error)
+	AckTask(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error)
+	NackTask(ctx context.Context, queue string, id, receipt uint64, delay time.Duration, maxAttempts int) (domain.Task, error)
+	BuryTask(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error)
+	PeekTasks(ctx context.Context, queue, status string, limit int) ([]domain.Task, error)
+}
+
+type TransferRepository interface {
+	ScanPayloads(ctx context.Context, prefix, after string, limit int) ([]domain.Payload, error)
+	ExistingKeys(ctx context.Context, keys []string) (map[string]bool, error)
+	WriteBatch(ctx context.Context, payloads []domain.Payload, overwrite bool) []error
+}
+
+type ServerRepository interface {
+	Ping(context.Context) (string, error)
+}
+
+type MigrationRepository interface {
+	MigrationStatus(context.Context) ([]domain.Migration, error)
+	MigrateUp(ctx context.Context, target uint64) ([]domain.Migration, error)
+	MigrateDown(ctx context.Context, target uint64) ([]domain.Migration, error)
+}
+
+type AuditRepository interface {
+	SelectAudit(context.Context, domain.AuditQuery) ([]domain.AuditEntry, error)
+}
+
+type HistoryRepository interface {
+	SelectVersions(ctx context.Context, key string, before uint64, limit int) ([]domain.Version, error)
+	SelectVersion(ctx context.Context, key string, version uint64) (domain.Version, error)
+	RestoreVersion(ctx context.Context, key string, version uint64) (domain.Version, error)
+}
+
+type TreeRepository interface {
+	SelectChildren(ctx context.Context, prefix, after string, limit int) ([]domain.Child, error)
+	ScanKeys(ctx context.Context, prefix, after string, limit int) ([]string, error)
+	DeleteKeys(ctx context.Context, keys []string) (int, error)
+}
+
+type CollectionRepository interface {
+	CreateCollection(ctx context.Context, c domain.Collection) error
+	DropCollection(ctx context.Context, name string) error
+	SelectCollection(ctx context.Context, name string) (domain.Collection, error)
+	SelectCollections(ctx context.Context) ([]domain.Collection, error)
+	SelectItem(ctx context.Context, c domain.Collection, key []any) (domain.Item, error)
+	InsertItem(ctx context.Context, c domain.Collection, item domain.Item) error
+	ReplaceItem(ctx context.Context, c domain.Collection, item domain.Item) error
+	DeleteItem(ctx context.Context, c domain.Collection, key []any) (domain.Item, error)
+	ScanItems(ctx context.Context, c domain.Collection, prefix, after []any, limit int) ([]domain.Item, error)
+	SelectItemsByIndex(ctx context.Context, c domain.Collection, q domain.Query) (domain.ItemPage, error)
+}
@@ -1,12 +1,105 @@
 package interfaces
 
 import (
+	"context"
+	"io"
 	"tarantool-app/internal/domain"
+	"time"
 )
 
 type UserUseCase interface {
-	Create(domain.Payload) error
-	Update(domain.Payload) error
-	Delete(domain.Payload) (domain.Payload, error)
-	Read(domain.Payload) (domain.Payload, error)
+	Create(context.Context, domain.Payload) error
+	Update(context.Context, domain.Payload) error
+	Delete(context.Context, domain.Payload) (domain.Payload, error)
+	Undelete(context.Context, domain.Payload) (domain.Payload, error)
+	Read(context.Context, domain.Payload) (domain.Payload, error)
+}
+
+type BlobUseCase interface {
+	Upload(ctx context.Context, key, contentType string, body io.Reader) (domain.BlobMeta, error)
+	Open(ctx context.Context, key string) (domain.BlobMeta, io.ReadSeeker, error)
+	Delete(ctx context.Context, key string) (domain.BlobMeta, error)
+}
+
+type SchemaUseCase interface {
+	List(context.Context) ([]domain.Schema, error)
+	Register(context.Context, domain.Schema) error
+	Unregister(ctx context.Context, prefix string) (domain.Schema, error)
+}
+
+type Validator interface {
+	Validate(key string, value map[string]any) error
+	Covers(key string) bool
+}
+
+type QueryUseCase interface {
+	Query(ctx context.Context, field, eq string, limit int, cursor string) (domain.Page, error)
+}
+
+type SQLUseCase interface {
+	Query(ctx context.Context, statement string, params []any, limit int) (domain.SQLResult, error)
+}
+
+type CounterUseCase interface {
+	Increment(ctx context.Context, key, path string, delta float64, min, max *float64) (any, error)
+}
+
+type LockUseCase interface {
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (domain.Lock, error)
+	Renew(ctx context.Context, name, owner string, token uint64, ttl time.Duration) (domain.Lock, error)
+	Release(ctx context.Context, name, owner string, token uint64) (domain.Lock, error)
+}
+
+type Leader interface {
+	IsLeader() bool
+}
+
+type QueueUseCase interface {
+	Put(ctx context.Context, queue string, payload any, delay time.Duration) (domain.Task, error)
+	Take(ctx context.Context, queue string, visibility, wait time.Duration) (domain.Task, error)
+	Ack(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error)
+	Nack(ctx context.Context, queue string, id, receipt uint64, delay time.Duration) (domain.Task, error)
+	Bury(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error)
+	Peek(ctx context.Context, queue, status string, limit int) ([]domain.Task, error)
+}
+
+type TransferUseCase interface {
+	Export(ctx context.Context, w io.Writer, prefix, format string) (int, error)
+	Import(ctx context.Context, r io.Reader, opts domain.ImportOptions) (domain.ImportReport, error)
+}
+
+type MigrationUseCase interface {
+	Status(context.Context) ([]domain.Migration, error)
+	Version(context.Context) (uint64, error)
+	Up(ctx context.Context, target uint64) ([]domain.Migration, error)
+	Down(ctx context.Context, target uint64) ([]domain.Migration, error)
+	Check(context.Context) error
+}
+
+type AuditUseCase interface {
+	Query(context.Context, domain.AuditQuery) ([]domain.AuditEntry, error)
+	Export(ctx context.Context, w io.Writer, q domain.AuditQuery) (int, error)
+}
+
+type HistoryUseCase interface {
+	Versions(ctx context.Context, key string, before uint64, limit int) ([]domain.Version, error)
+	Version(ctx context.Context, key string, version uint64) (domain.Version, error)
+	Restore(ctx context.Context, key string, version uint64) (domain.Version, error)
+}
+
+type TreeUseCase interface {
+	Children(ctx context.Context, path, after string, limit int) ([]domain.Child, error)
+	DeleteTree(ctx context.Context, path string) (int, error)
+}
+
+type CollectionUseCase interface {
+	Create(ctx context.Context, c domain.Collection) (domain.Collection, error)
+	Drop(ctx context.Context, name string) error
+	List(ctx context.Context) ([]domain.Collection, error)
+	Insert(ctx context.Context, name string, key []string, value map[string]any) (domain.Item, error)
+	Get(ctx context.Context, name string, key []string) (domain.Item, error)
+	Put(ctx context.Context, name string, key []string, value map[string]any) (domain.Item, error)
+	Delete(ctx context.Context, name string, key []string) (domain.Item, error)
+	Scan(ctx context.Context, name string, prefix, after []string, limit int) ([]domain.Item, error)
+	Query(ctx context.Context, name, index, eq string, limit int, cursor string) (domain.ItemPage, error)
 }
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"tarantool-app/config"
 	"tarantool-app/internal/domain"
 	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
 	"time"
 
 	"github.com/tarantool/go-iproto"
@@ -15,14 +17,44 @@ import (
 	_ "github.com/tarantool/go-tarantool/v2/uuid"
 )
 
+// Spaces of domain.DefaultCollection: its keys in memory and those demoted
+// to disk. The Lua functions behind its audit, history, trash, tiers and
+// blobs are bound to them, so the repository serves no other collection
+// this way; see tt_collection.go for the others.
+const (
+	defaultSpace = "kv_storage"
+	coldSpace    = "kv_cold"
+)
+
 type Tarantool struct {
-	conn *tarantool.Connection
-	log  interfaces.Logger
+	conn    *tarantool.Connection
+	log     interfaces.Logger
+	codec   *valueCodec
+	cipher  *valueCipher
+	indexes []indexedPath
+	history *historyPolicy
+	trash   config.TrashConfig
+	tiering config.TieringConfig
 }
 
 var _ interfaces.Repository = Tarantool{} // Tarantool must satisfy Repository
 
 func NewTarantoolRepository(cfg config.Config, log interfaces.Logger) (Tarantool, error) {
+	codec, err := newValueCodec(cfg.Compression)
+	if err != nil {
+		return Tarantool{}, err
+	}
+
+	cipher, err := newValueCipher(cfg.Encryption)
+	if err != nil {
+		return Tarantool{}, err
+	}
+
+	indexes, err := parseIndexes(cfg.Indexes)
+	if err != nil {
+		return Tarantool{}, err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
@@ -45,12 +77,12 @@ func NewTarantoolRepository(cfg config.Config, log interfaces.Logger) (Tarantool
 	if err != nil {
 		log.Debug("Connection refused",
 			"user", cfg.Storage.Credentials.Username,
-			err,
+			"error", err,
 		)
 		return Tarantool{}, err
 	}
 
-	return Tarantool{conn: conn, log: log}, nil
+	return Tarantool{conn: conn, log: log, codec: codec, cipher: cipher, indexes: indexes, history: newHistoryPolicy(cfg.History), trash: cfg.Trash, tiering: cfg.Tiering}, nil
 }
 
 func (tt Tarantool) Close() {
@@ -62,34 +94,41 @@ func (tt Tarantool) Close() {
 }
 
 // POST ---> Insert
-func (tt Tarantool) Insert(rq domain.Payload) error {
-	request := tarantool.NewInsertRequest("kv_storage").Tuple(&rq)
+func (tt Tarantool) Insert(ctx context.Context, rq domain.Payload) error {
+	return tt.insertRecord(ctx, rq)
+}
+
+// insertRecord encodes the payload and inserts it, failing with
+// ErrAlreadyExists when the key exists.
+func (tt Tarantool) insertRecord(ctx context.Context, p domain.Payload) error {
+	rec, err := tt.toRecord(p)
+	if err != nil {
+		return ErrInsertOperationFail
+	}
 
-	futureResp, err := tt.conn.Do(request).GetResponse()
+	request := tt.auditedWrite(ctx, rec.Key, p.Value, "insert", &rec)
 
-	if futureResp.Header().Error != tarantool.ErrorNo {
-		if tntErr, ok := err.(tarantool.Error); !ok || tntErr.Code == iproto.ER_TUPLE_FOUND {
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && tntErr.Code == iproto.ER_TUPLE_FOUND {
 			return ErrAlreadyExists
-		} else {
-			return ErrInsertOperationFail
 		}
+		return ErrInsertOperationFail
 	}
 
 	return nil
 }
 
 // GET ---> Select
-func (tt Tarantool) Select(rq domain.Payload) (domain.Payload, error) {
-	request := tarantool.NewSelectRequest("kv_storage").Key(tarantool.StringKey{S: rq.Key})
-
-	future := tt.conn.Do(request)
+func (tt Tarantool) Select(ctx context.Context, rq domain.Payload) (domain.Payload, error) {
+	future := tt.conn.Do(tt.getRequest(ctx, rq.Key))
 
 	futureResp, err := future.GetResponse()
 	if err != nil {
 		return domain.Payload{}, err
 	}
 
-	var result []domain.Payload
+	var result []record
 	errDecode := futureResp.DecodeTyped(&result)
 	if errDecode != nil {
 		return domain.Payload{}, ErrSelectOperationFail
@@ -99,19 +138,36 @@ func (tt Tarantool) Select(rq domain.Payload) (domain.Payload, error) {
 		return domain.Payload{}, ErrNotFound
 	}
 
-	return result[0], nil
+	payload, err := tt.toPayload(result[0])
+	if err != nil {
+		utils.LoggerFromContext(ctx, tt.log).Error("Failed to decode stored value",
+			"key", rq.Key,
+			"error", err,
+		)
+		return domain.Payload{}, ErrSelectOperationFail
+	}
+
+	return payload, nil
 }
 
 // PUT ---> Update
-func (tt Tarantool) Update(rq domain.Payload) error {
-	request := tarantool.NewUpdateRequest("kv_storage").
-		Key(tarantool.StringKey{S: rq.Key}).
-		Operations(tarantool.NewOperations().Assign(1, &rq.Value))
+func (tt Tarantool) Update(ctx context.Context, rq domain.Payload) error {
+	rec, err := tt.toRecord(rq)
+	if err != nil {
+		return ErrUpdateOperationFail
+	}
+
+	request := tt.auditedWrite(ctx, rq.Key, rq.Value, "update", []any{rq.Key}, []any{
+		[]any{"=", "value", rec.Value},
+		[]any{"=", "codec", optionalString(rec.Codec)},
+		[]any{"=", "kid", optionalString(rec.KeyID)},
+		[]any{"=", "attrs", optionalMap(rec.Attrs)},
+	})
 
 	future := tt.conn.Do(request)
 
-	var result []domain.Payload
-	err := future.GetTyped(&result)
+	var result []record
+	err = future.GetTyped(&result)
 	if err != nil {
 		return ErrUpdateOperationFail
 	}
@@ -124,12 +180,10 @@ func (tt Tarantool) Update(rq domain.Payload) error {
 }
 
 // DELETE ---> Delete
-func (tt Tarantool) Delete(rq domain.Payload) (domain.Payload, error) {
-	request := tarantool.NewDeleteRequest("kv_storage").Key(tarantool.StringKey{S: rq.Key})
-
-	future := tt.conn.Do(request)
+func (tt Tarantool) Delete(ctx context.Context, rq domain.Payload) (domain.Payload, error) {
+	future := tt.conn.Do(tt.deleteRequest(ctx, rq.Key))
 
-	var result []domain.Payload
+	var result []record
 	err := future.GetTyped(&result)
 	if err != nil {
 		return domain.Payload{}, ErrDeleteOperationFail
@@ -139,5 +193,5 @@ func (tt Tarantool) Delete(rq domain.Payload) (domain.Payload, error) {
 		return domain.Payload{}, ErrNotFound
 	}
 
-	return result[0], nil
+	return tt.toPayload(result[0])
 }
@@ -0,0 +1,80 @@
+// Audit trail of changes of `kv_storage` and `kv_blobs`.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+
+	"github.com/tarantool/go-tarantool/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var _ interfaces.AuditRepository = Tarantool{} // Tarantool must satisfy AuditRepository
+
+// audited returns a call of `kv_audited` running op, a request of
+// `kv_storage` or a function changing key or its blob, on behalf of the
+// actor of ctx. The audit entry and the version of the value are written
+// by Tarantool in the transaction of the change.
+func (tt Tarantool) audited(ctx context.Context, key, op string, args ...any) *tarantool.CallRequest {
+	return tarantool.NewCallRequest("kv_audited").
+		Args(append([]any{utils.ActorFromContext(ctx), tt.history.forKey(key), op}, args...)).
+		Context(ctx)
+}
+
+// auditedWrite is audited for op writing the plain value of key. Writing
+// the value key already holds, e.g. sealed again with a new nonce, is
+// neither recorded nor versioned.
+func (tt Tarantool) auditedWrite(ctx context.Context, key string, value map[string]any, op string, args ...any) *tarantool.CallRequest {
+	actor := utils.ActorFromContext(ctx)
+	actor.ValueHash = valueHash(value)
+	return tarantool.NewCallRequest("kv_audited").
+		Args(append([]any{actor, tt.history.forKey(key), op}, args...)).
+		Context(ctx)
+}
+
+// valueHash returns the SHA-256 of the msgpack encoding of value with
+// sorted map keys, empty if it cannot be encoded.
+func valueHash(value map[string]any) string {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(value); err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// SelectAudit returns up to q.Limit entries matching the query, oldest
+// first.
+func (tt Tarantool) SelectAudit(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	request := tarantool.NewCallRequest("kv_audit_query").
+		Args([]any{optionalString(q.Key), optionalTime(q.Since), optionalTime(q.Until), q.After, q.Limit}).
+		Context(ctx)
+
+	var result [][]domain.AuditEntry
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+// optionalTime maps a zero time to nil for open bounds, other times to
+// milliseconds since the epoch.
+func optionalTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixMilli()
+}
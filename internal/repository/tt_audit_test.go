@@ -0,0 +1,28 @@
+package repository
+
+import "testing"
+
+func TestValueHash(t *testing.T) {
+	value := map[string]any{"name": "Ann", "address": map[string]any{"city": "Oslo", "zip": "0150"}}
+
+	tests := []struct {
+		name  string
+		other map[string]any
+		same  bool
+	}{
+		{"equal value", map[string]any{"address": map[string]any{"zip": "0150", "city": "Oslo"}, "name": "Ann"}, true},
+		{"changed field", map[string]any{"name": "Ann", "address": map[string]any{"city": "Bergen", "zip": "0150"}}, false},
+		{"missing field", map[string]any{"name": "Ann"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order varies, hash each side a few times.
+			for range 10 {
+				if got := valueHash(value) == valueHash(tt.other); got != tt.same {
+					t.Fatalf("hashes equal = %v, want %v", got, tt.same)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"time"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error code raised by the blob functions when an upload expired or not
+// every chunk was uploaded.
+const errCodeBlobIncomplete iproto.Error = 10001
+
+var _ interfaces.BlobRepository = Tarantool{} // Tarantool must satisfy BlobRepository
+
+// BeginBlob registers an upload of a new generation. Its chunks are
+// collected unless it is committed within timeout.
+func (tt Tarantool) BeginBlob(ctx context.Context, key, generation string, timeout time.Duration) error {
+	request := tarantool.NewCallRequest("kv_blob_begin").
+		Args([]any{key, generation, timeout.Milliseconds()}).
+		Context(ctx)
+
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		return ErrInsertOperationFail
+	}
+
+	return nil
+}
+
+func (tt Tarantool) InsertChunk(ctx context.Context, chunk domain.BlobChunk) error {
+	request := tarantool.NewCallRequest("kv_blob_put_chunk").
+		Args([]any{chunk.Key, chunk.Generation, chunk.Seq, chunk.Data}).
+		Context(ctx)
+
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && tntErr.Code == errCodeBlobIncomplete {
+			return ErrBlobIncomplete
+		}
+		return ErrInsertOperationFail
+	}
+
+	return nil
+}
+
+func (tt Tarantool) SelectChunk(ctx context.Context, key, generation string, seq uint64) (domain.BlobChunk, error) {
+	request := tarantool.NewSelectRequest("kv_chunks").
+		Key([]any{key, generation, seq}).
+		Context(ctx)
+
+	var result []domain.BlobChunk
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.BlobChunk{}, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.BlobChunk{}, ErrNotFound
+	}
+
+	return result[0], nil
+}
+
+// CommitBlob publishes an uploaded generation. The replaced generation
+// stays readable for retention.
+func (tt Tarantool) CommitBlob(ctx context.Context, meta domain.BlobMeta, retention time.Duration) (domain.BlobMeta, error) {
+	request := tt.audited(ctx, meta.Key, "kv_blob_commit",
+		meta.Key, meta.Generation, meta.Size, meta.ChunkSize, meta.Chunks, meta.ContentType, retention.Milliseconds())
+
+	var result []domain.BlobMeta
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && tntErr.Code == errCodeBlobIncomplete {
+			return domain.BlobMeta{}, ErrBlobIncomplete
+		}
+		return domain.BlobMeta{}, ErrCommitOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.BlobMeta{}, ErrCommitOperationFail
+	}
+
+	return result[0], nil
+}
+
+func (tt Tarantool) AbortBlob(ctx context.Context, key, generation string) error {
+	request := tarantool.NewCallRequest("kv_blob_abort").
+		Args([]any{key, generation}).
+		Context(ctx)
+
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		return ErrDeleteOperationFail
+	}
+
+	return nil
+}
+
+func (tt Tarantool) SelectBlob(ctx context.Context, key string) (domain.BlobMeta, error) {
+	request := tarantool.NewSelectRequest("kv_blobs").
+		Key(tarantool.StringKey{S: key}).
+		Context(ctx)
+
+	var result []domain.BlobMeta
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.BlobMeta{}, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.BlobMeta{}, ErrNotFound
+	}
+
+	return result[0], nil
+}
+
+// DeleteBlob deletes a blob, whose chunks stay readable for retention.
+func (tt Tarantool) DeleteBlob(ctx context.Context, key string, retention time.Duration) (domain.BlobMeta, error) {
+	request := tt.audited(ctx, key, "kv_blob_delete", key, retention.Milliseconds())
+
+	var result []*domain.BlobMeta
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.BlobMeta{}, ErrDeleteOperationFail
+	}
+
+	if len(result) == 0 || result[0] == nil {
+		return domain.BlobMeta{}, ErrNotFound
+	}
+
+	return *result[0], nil
+}
@@ -0,0 +1,200 @@
+// Transparent compression of stored values.
+
+package repository
+
+import (
+	"expvar"
+	"fmt"
+	"strings"
+	"tarantool-app/config"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	CodecNone    = "none"
+	CodecMsgpack = "msgpack" // binary msgpack, set for encrypted uncompressed values
+	CodecZstd    = "zstd"
+	CodecSnappy  = "snappy"
+)
+
+// Compression metrics exported through expvar under "compression".
+var (
+	compressionMetrics      = expvar.NewMap("compression")
+	compressedValues        = new(expvar.Int)
+	uncompressedValues      = new(expvar.Int)
+	compressionInputBytes   = new(expvar.Int)
+	compressionOutputBytes  = new(expvar.Int)
+	decompressedValues      = new(expvar.Int)
+	compressionRatioPercent = expvar.Func(func() any {
+		out := compressionOutputBytes.Value()
+		if out == 0 {
+			return 0
+		}
+		return compressionInputBytes.Value() * 100 / out
+	})
+)
+
+func init() {
+	compressionMetrics.Set("compressed_values", compressedValues)
+	compressionMetrics.Set("uncompressed_values", uncompressedValues)
+	compressionMetrics.Set("input_bytes", compressionInputBytes)
+	compressionMetrics.Set("output_bytes", compressionOutputBytes)
+	compressionMetrics.Set("decompressed_values", decompressedValues)
+	compressionMetrics.Set("ratio_percent", compressionRatioPercent)
+}
+
+// valueCodec compresses msgpack-encoded values that exceed the threshold
+// when compression makes them smaller.
+// Compressed values are stored as binary alongside the codec name, so
+// plain and compressed tuples coexist in the same space.
+type valueCodec struct {
+	enabled   bool
+	algorithm string
+	threshold int
+	rules     []config.CompressionRule
+	zstdEnc   *zstd.Encoder
+	zstdDec   *zstd.Decoder
+}
+
+func newValueCodec(cfg config.CompressionConfig) (*valueCodec, error) {
+	codec := &valueCodec{
+		enabled:   cfg.Enabled,
+		algorithm: cfg.Algorithm,
+		threshold: cfg.Threshold,
+		rules:     cfg.Rules,
+	}
+
+	for _, algorithm := range append([]string{cfg.Algorithm}, ruleAlgorithms(cfg.Rules)...) {
+		switch algorithm {
+		case CodecNone, CodecZstd, CodecSnappy:
+		default:
+			return nil, fmt.Errorf("unknown compression algorithm %q", algorithm)
+		}
+	}
+
+	var err error
+	if codec.zstdEnc, err = zstd.NewWriter(nil); err != nil {
+		return nil, err
+	}
+	if codec.zstdDec, err = zstd.NewReader(nil); err != nil {
+		return nil, err
+	}
+
+	return codec, nil
+}
+
+func ruleAlgorithms(rules []config.CompressionRule) []string {
+	algorithms := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		algorithms = append(algorithms, rule.Algorithm)
+	}
+	return algorithms
+}
+
+// algorithmFor picks the algorithm of the longest matching prefix rule.
+func (vc *valueCodec) algorithmFor(key string) string {
+	if !vc.enabled {
+		return CodecNone
+	}
+
+	algorithm, matched := vc.algorithm, -1
+	for _, rule := range vc.rules {
+		if strings.HasPrefix(key, rule.Prefix) && len(rule.Prefix) > matched {
+			algorithm, matched = rule.Algorithm, len(rule.Prefix)
+		}
+	}
+	return algorithm
+}
+
+// encode returns the value to store and the codec it was compressed with.
+// Values left uncompressed are returned with an empty codec, as raw, their
+// MessagePack encoding, when it is known so that it is not encoded again.
+func (vc *valueCodec) encode(key string, value map[string]any, raw msgpack.RawMessage) (any, string, error) {
+	algorithm := vc.algorithmFor(key)
+	if algorithm == CodecNone {
+		return plainValue(value, raw), "", nil
+	}
+
+	if raw == nil {
+		var err error
+		if raw, err = msgpack.Marshal(value); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(raw) < vc.threshold {
+		uncompressedValues.Add(1)
+		return raw, "", nil
+	}
+
+	var compressed []byte
+	switch algorithm {
+	case CodecZstd:
+		compressed = vc.zstdEnc.EncodeAll(raw, nil)
+	case CodecSnappy:
+		compressed = snappy.Encode(nil, raw)
+	}
+
+	// Values that do not shrink are not worth decompressing on every read.
+	if len(compressed) >= len(raw) {
+		uncompressedValues.Add(1)
+		return raw, "", nil
+	}
+
+	compressedValues.Add(1)
+	compressionInputBytes.Add(int64(len(raw)))
+	compressionOutputBytes.Add(int64(len(compressed)))
+
+	return compressed, algorithm, nil
+}
+
+func plainValue(value map[string]any, raw msgpack.RawMessage) any {
+	if raw != nil {
+		return raw
+	}
+	return value
+}
+
+// decode restores the plain map from a stored value.
+func (vc *valueCodec) decode(stored any, codec string) (map[string]any, error) {
+	if codec == "" {
+		value, ok := stored.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value type %T", stored)
+		}
+		return value, nil
+	}
+
+	compressed, ok := stored.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected compressed value type %T", stored)
+	}
+
+	var raw []byte
+	var err error
+	switch codec {
+	case CodecMsgpack:
+		raw = compressed
+	case CodecZstd:
+		raw, err = vc.zstdDec.DecodeAll(compressed, nil)
+	case CodecSnappy:
+		raw, err = snappy.Decode(nil, compressed)
+	default:
+		err = fmt.Errorf("unknown codec %q", codec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if codec != CodecMsgpack {
+		decompressedValues.Add(1)
+	}
+
+	var value map[string]any
+	if err := msgpack.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
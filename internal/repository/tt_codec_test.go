@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"tarantool-app/config"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestValueCodecRoundTrip(t *testing.T) {
+	codec, err := newValueCodec(config.CompressionConfig{
+		Enabled:   true,
+		Algorithm: CodecZstd,
+		Threshold: 64,
+		Rules: []config.CompressionRule{
+			{Prefix: "logs/", Algorithm: CodecSnappy},
+			{Prefix: "logs/raw/", Algorithm: CodecNone},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newValueCodec: %v", err)
+	}
+
+	small := map[string]any{"name": "Ann"}
+	large := map[string]any{"text": strings.Repeat("compressible ", 100)}
+
+	tests := []struct {
+		name  string
+		key   string
+		value map[string]any
+		codec string
+	}{
+		{"below threshold", "users/1", small, ""},
+		{"default algorithm", "users/2", large, CodecZstd},
+		{"rule", "logs/1", large, CodecSnappy},
+		{"longest prefix wins", "logs/raw/1", large, ""},
+		{"rule below threshold", "logs/2", small, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, name, err := codec.encode(tt.key, tt.value, nil)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if name != tt.codec {
+				t.Fatalf("codec %q, want %q", name, tt.codec)
+			}
+
+			// Decode the value as read back from Tarantool.
+			rec := storedRecord(t, record{Key: tt.key, Value: stored, Codec: name})
+			value, err := codec.decode(rec.Value, rec.Codec)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(value, tt.value) {
+				t.Fatalf("decoded %v, want %v", value, tt.value)
+			}
+		})
+	}
+}
+
+func TestValueCodecIncompressible(t *testing.T) {
+	codec, err := newValueCodec(config.CompressionConfig{Enabled: true, Algorithm: CodecSnappy, Threshold: 1})
+	if err != nil {
+		t.Fatalf("newValueCodec: %v", err)
+	}
+
+	// Distinct bytes leave snappy nothing to shrink.
+	var text bytes.Buffer
+	for i := range 200 {
+		text.WriteByte(byte(i))
+	}
+	value := map[string]any{"bytes": text.String()}
+
+	stored, name, err := codec.encode("k", value, nil)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if name != "" {
+		t.Fatalf("codec %q, want the value left uncompressed", name)
+	}
+	if _, ok := stored.(msgpack.RawMessage); !ok {
+		t.Fatalf("stored %T, want its msgpack encoding", stored)
+	}
+}
+
+func TestValueCodecCoexistence(t *testing.T) {
+	// Records written with compression disabled, then enabled with zstd and
+	// later switched to snappy are all read by the current codec.
+	large := map[string]any{"text": strings.Repeat("compressible ", 100)}
+	var records []record
+	for _, cfg := range []config.CompressionConfig{
+		{Enabled: false, Algorithm: CodecZstd},
+		{Enabled: true, Algorithm: CodecZstd, Threshold: 64},
+		{Enabled: true, Algorithm: CodecSnappy, Threshold: 64},
+	} {
+		codec, err := newValueCodec(cfg)
+		if err != nil {
+			t.Fatalf("newValueCodec: %v", err)
+		}
+		stored, name, err := codec.encode("k", large, nil)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		records = append(records, storedRecord(t, record{Key: "k", Value: stored, Codec: name}))
+	}
+
+	current, err := newValueCodec(config.CompressionConfig{Enabled: true, Algorithm: CodecSnappy, Threshold: 64})
+	if err != nil {
+		t.Fatalf("newValueCodec: %v", err)
+	}
+	for _, rec := range records {
+		value, err := current.decode(rec.Value, rec.Codec)
+		if err != nil {
+			t.Fatalf("decode of codec %q: %v", rec.Codec, err)
+		}
+		if !reflect.DeepEqual(value, large) {
+			t.Fatalf("decoded %v of codec %q, want %v", value, rec.Codec, large)
+		}
+	}
+}
+
+func TestValueCodecDecodeErrors(t *testing.T) {
+	codec, err := newValueCodec(config.CompressionConfig{Enabled: true, Algorithm: CodecZstd})
+	if err != nil {
+		t.Fatalf("newValueCodec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stored any
+		codec  string
+	}{
+		{"plain value is not a map", "text", ""},
+		{"compressed value is not binary", map[string]any{}, CodecZstd},
+		{"unknown codec", []byte{1}, "lz4"},
+		{"corrupt zstd", []byte("not zstd"), CodecZstd},
+		{"corrupt snappy", []byte{0xff}, CodecSnappy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.decode(tt.stored, tt.codec); err == nil {
+				t.Fatalf("decode(%v, %q) succeeded, want an error", tt.stored, tt.codec)
+			}
+		})
+	}
+}
+
+// storedRecord returns the record as Tarantool returns it once written.
+func storedRecord(t *testing.T, rec record) record {
+	t.Helper()
+
+	data, err := msgpack.Marshal(&rec)
+	if err != nil {
+		t.Fatalf("marshal record: %v", err)
+	}
+	var stored record
+	if err := msgpack.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("unmarshal record: %v", err)
+	}
+	return stored
+}
@@ -0,0 +1,331 @@
+// Items of collections, spaces declared in the `collections` section of
+// the configuration with typed and composite primary keys.
+//
+// Items are written by plain requests of their space, not through
+// kv_audited: they are stored as they are, without schemas, compression or
+// encryption, and their changes are neither audited nor versioned.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Error codes raised by the collection functions.
+const (
+	errCodeCollectionExists   iproto.Error = 10014
+	errCodeCollectionNotFound iproto.Error = 10015
+)
+
+var _ interfaces.CollectionRepository = Tarantool{} // Tarantool must satisfy CollectionRepository
+
+// collectionRecord is a tuple of `kv_collections`.
+type collectionRecord struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	Name      string
+	Engine    string
+	Key       []domain.KeyPart
+	Indexes   []domain.CollectionIndex
+	CreatedAt int64
+}
+
+func (cr collectionRecord) toCollection() domain.Collection {
+	return domain.Collection{Name: cr.Name, Engine: cr.Engine, Key: cr.Key, Indexes: cr.Indexes}
+}
+
+// CreateCollection creates the space of a collection defined over HTTP
+// and registers it in `kv_collections`.
+func (tt Tarantool) CreateCollection(ctx context.Context, c domain.Collection) error {
+	indexes := c.Indexes
+	if indexes == nil {
+		indexes = []domain.CollectionIndex{}
+	}
+	request := tarantool.NewCallRequest("kv_collection_create").
+		Args([]any{c.Name, c.Engine, c.Key, indexes}).
+		Context(ctx)
+
+	var result []collectionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && (tntErr.Code == errCodeCollectionExists || tntErr.Code == iproto.ER_TUPLE_FOUND) {
+			return ErrCollectionExists
+		}
+		utils.LoggerFromContext(ctx, tt.log).Error("Failed to create collection",
+			"collection", c.Name,
+			"error", err,
+		)
+		return ErrInsertOperationFail
+	}
+	return nil
+}
+
+// DropCollection drops a collection defined over HTTP together with its
+// items.
+func (tt Tarantool) DropCollection(ctx context.Context, name string) error {
+	request := tarantool.NewCallRequest("kv_collection_drop").
+		Args([]any{name}).
+		Context(ctx)
+
+	var result []collectionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && tntErr.Code == errCodeCollectionNotFound {
+			return domain.ErrCollectionNotFound
+		}
+		return ErrDeleteOperationFail
+	}
+	return nil
+}
+
+// SelectCollection returns the collection defined over HTTP with the name.
+func (tt Tarantool) SelectCollection(ctx context.Context, name string) (domain.Collection, error) {
+	request := tarantool.NewSelectRequest("kv_collections").
+		Key(tarantool.StringKey{S: name}).
+		Context(ctx)
+
+	var result []collectionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.Collection{}, ErrSelectOperationFail
+	}
+	if len(result) == 0 {
+		return domain.Collection{}, domain.ErrCollectionNotFound
+	}
+	return result[0].toCollection(), nil
+}
+
+// SelectCollections returns every collection defined over HTTP, by name.
+func (tt Tarantool) SelectCollections(ctx context.Context) ([]domain.Collection, error) {
+	request := tarantool.NewSelectRequest("kv_collections").
+		Iterator(tarantool.IterAll).
+		Context(ctx)
+
+	var result []collectionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	collections := make([]domain.Collection, len(result))
+	for i, cr := range result {
+		collections[i] = cr.toCollection()
+	}
+	return collections, nil
+}
+
+// itemTuples decodes the tuples of a collection, each key part by its
+// declared type, so that parts compare equal to the parsed ones.
+type itemTuples struct {
+	key   []domain.KeyPart
+	items []domain.Item
+}
+
+func (it *itemTuples) DecodeMsgpack(d *msgpack.Decoder) error {
+	n, err := d.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+
+	it.items = make([]domain.Item, 0, max(n, 0))
+	for range n {
+		item, err := it.decodeItem(d)
+		if err != nil {
+			return err
+		}
+		it.items = append(it.items, item)
+	}
+	return nil
+}
+
+func (it *itemTuples) decodeItem(d *msgpack.Decoder) (domain.Item, error) {
+	fields, err := d.DecodeArrayLen()
+	if err != nil {
+		return domain.Item{}, err
+	}
+	if fields < len(it.key)+1 {
+		return domain.Item{}, fmt.Errorf("tuple has %d fields, want %d", fields, len(it.key)+1)
+	}
+
+	item := domain.Item{Key: make([]any, len(it.key))}
+	for i, part := range it.key {
+		if item.Key[i], err = decodeKeyPart(d, part.Type); err != nil {
+			return domain.Item{}, err
+		}
+	}
+	if err := d.Decode(&item.Value); err != nil {
+		return domain.Item{}, err
+	}
+	for range fields - len(it.key) - 1 {
+		if err := d.Skip(); err != nil {
+			return domain.Item{}, err
+		}
+	}
+	return item, nil
+}
+
+func decodeKeyPart(d *msgpack.Decoder, kind string) (any, error) {
+	switch kind {
+	case domain.KeyPartUnsigned:
+		return d.DecodeUint64()
+	case domain.KeyPartInteger:
+		return d.DecodeInt64()
+	case domain.KeyPartUUID:
+		v, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := v.(uuid.UUID); ok {
+			return id, nil
+		}
+		return nil, fmt.Errorf("key part is %T, want a UUID", v)
+	default:
+		return d.DecodeString()
+	}
+}
+
+// doItems runs a request returning tuples of the collection.
+func (tt Tarantool) doItems(c domain.Collection, request tarantool.Request) ([]domain.Item, error) {
+	result := itemTuples{key: c.Key}
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, err
+	}
+	return result.items, nil
+}
+
+func itemTuple(item domain.Item) []any {
+	return append(append(make([]any, 0, len(item.Key)+1), item.Key...), item.Value)
+}
+
+// SelectItem returns the item of the collection with the full key.
+func (tt Tarantool) SelectItem(ctx context.Context, c domain.Collection, key []any) (domain.Item, error) {
+	request := tarantool.NewSelectRequest(c.Name).
+		Key(key).
+		Limit(1).
+		Context(ctx)
+
+	items, err := tt.doItems(c, request)
+	if err != nil {
+		return domain.Item{}, ErrSelectOperationFail
+	}
+	if len(items) == 0 {
+		return domain.Item{}, ErrNotFound
+	}
+	return items[0], nil
+}
+
+// InsertItem stores a new item, unaudited.
+func (tt Tarantool) InsertItem(ctx context.Context, c domain.Collection, item domain.Item) error {
+	request := tarantool.NewInsertRequest(c.Name).
+		Tuple(itemTuple(item)).
+		Context(ctx)
+
+	if _, err := tt.doItems(c, request); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) && tntErr.Code == iproto.ER_TUPLE_FOUND {
+			return ErrAlreadyExists
+		}
+		return ErrInsertOperationFail
+	}
+	return nil
+}
+
+// ReplaceItem stores the item, replacing the one with the same key,
+// unaudited.
+func (tt Tarantool) ReplaceItem(ctx context.Context, c domain.Collection, item domain.Item) error {
+	request := tarantool.NewReplaceRequest(c.Name).
+		Tuple(itemTuple(item)).
+		Context(ctx)
+
+	if _, err := tt.doItems(c, request); err != nil {
+		return ErrInsertOperationFail
+	}
+	return nil
+}
+
+// DeleteItem deletes the item of the collection with the full key and
+// returns it, unaudited and without moving it to the trash.
+func (tt Tarantool) DeleteItem(ctx context.Context, c domain.Collection, key []any) (domain.Item, error) {
+	request := tarantool.NewDeleteRequest(c.Name).
+		Key(key).
+		Context(ctx)
+
+	items, err := tt.doItems(c, request)
+	if err != nil {
+		return domain.Item{}, ErrDeleteOperationFail
+	}
+	if len(items) == 0 {
+		return domain.Item{}, ErrNotFound
+	}
+	return items[0], nil
+}
+
+// ScanItems returns up to limit items of the collection whose key starts
+// with the parts of prefix, in the order of the primary index, after the
+// full key after or from the prefix when after is empty.
+func (tt Tarantool) ScanItems(ctx context.Context, c domain.Collection, prefix, after []any, limit int) ([]domain.Item, error) {
+	iterator, key := tarantool.IterGe, prefix
+	if len(after) > 0 {
+		iterator, key = tarantool.IterGt, after
+	}
+
+	request := tarantool.NewSelectRequest(c.Name).
+		Iterator(iterator).
+		Key(key).
+		Limit(uint32(limit)).
+		Context(ctx)
+
+	items, err := tt.doItems(c, request)
+	if err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	for i, item := range items {
+		for j, part := range prefix {
+			if item.Key[j] != part {
+				return items[:i], nil
+			}
+		}
+	}
+	return items, nil
+}
+
+// SelectItemsByIndex returns one page of the items whose indexed path
+// equals q.Key.
+func (tt Tarantool) SelectItemsByIndex(ctx context.Context, c domain.Collection, q domain.Query) (domain.ItemPage, error) {
+	request := tarantool.NewSelectRequest(c.Name).
+		Index(q.Index).
+		Iterator(tarantool.IterEq).
+		Key([]any{q.Key}).
+		Limit(uint32(q.Limit)).
+		FetchPos(true).
+		Context(ctx)
+	if len(q.After) > 0 {
+		request = request.After(q.After)
+	}
+
+	response, err := tt.conn.Do(request).GetResponse()
+	if err != nil {
+		return domain.ItemPage{}, ErrSelectOperationFail
+	}
+
+	result := itemTuples{key: c.Key}
+	if err := response.DecodeTyped(&result); err != nil {
+		return domain.ItemPage{}, ErrSelectOperationFail
+	}
+
+	page := domain.ItemPage{Items: result.items}
+	if selectResponse, ok := response.(*tarantool.SelectResponse); ok && len(result.items) == q.Limit {
+		if page.Next, err = selectResponse.Pos(); err != nil {
+			return domain.ItemPage{}, ErrSelectOperationFail
+		}
+	}
+	return page, nil
+}
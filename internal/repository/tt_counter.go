@@ -0,0 +1,257 @@
+// Atomic arithmetic on numbers inside stored values.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error codes raised by `kv_incr`.
+const (
+	errCodeValueEncoded iproto.Error = 10002
+	errCodeNotANumber   iproto.Error = 10003
+	errCodeOutOfBounds  iproto.Error = 10004
+)
+
+var errValueEncoded = errors.New("value is stored encoded")
+
+var _ interfaces.CounterRepository = Tarantool{} // Tarantool must satisfy CounterRepository
+
+// Increment applies the increment with a Tarantool arithmetic update on the
+// path inside a plain value. Compressed and encrypted values cannot be
+// updated in place, nor can values whose result must be validated: they
+// are decoded and swapped if unchanged instead. It returns the new number.
+func (tt Tarantool) Increment(ctx context.Context, inc domain.Increment) (any, error) {
+	if !tt.cipher.appliesTo(inc.Key) && inc.Validate == nil {
+		result, err := tt.incrementInPlace(ctx, inc)
+		if !errors.Is(err, errValueEncoded) {
+			return result, err
+		}
+	}
+	return tt.incrementEncoded(ctx, inc)
+}
+
+// incrementInPlace sends the delta to `kv_incr`, which adds it to the
+// number at the path inside the value.
+func (tt Tarantool) incrementInPlace(ctx context.Context, inc domain.Increment) (any, error) {
+	request := tt.audited(ctx, inc.Key, "kv_incr", inc.Key, inc.Path, numberArg(inc.Delta), optionalNumber(inc.Min), optionalNumber(inc.Max))
+
+	var result []record
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			switch tntErr.Code {
+			case errCodeValueEncoded:
+				return nil, errValueEncoded
+			case errCodeNotANumber:
+				return nil, ErrNotANumber
+			case errCodeOutOfBounds:
+				return nil, ErrOutOfBounds
+			}
+		}
+		return nil, ErrUpdateOperationFail
+	}
+
+	if len(result) == 0 {
+		return nil, ErrUpdateOperationFail
+	}
+
+	payload, err := tt.toPayload(result[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tt.refreshAttrs(ctx, result[0], payload.Value); err != nil {
+		return nil, err
+	}
+
+	number, _ := lookupPath(payload.Value, inc.Path)
+	return number, nil
+}
+
+// incrementEncoded retries a read-modify-swap until no concurrent write
+// interferes. The resulting value is validated before it is written.
+func (tt Tarantool) incrementEncoded(ctx context.Context, inc domain.Increment) (any, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		old, err := tt.selectRecord(ctx, inc.Key)
+		if errors.Is(err, ErrNotFound) {
+			value := make(map[string]any)
+			result, err := applyIncrement(value, inc)
+			if err != nil {
+				return nil, err
+			}
+			if inc.Validate != nil {
+				if err := inc.Validate(value); err != nil {
+					return nil, err
+				}
+			}
+
+			err = tt.insertRecord(ctx, domain.Payload{Key: inc.Key, Value: value})
+			if errors.Is(err, ErrAlreadyExists) {
+				continue
+			}
+			return result, err
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		payload, err := tt.toPayload(old)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := applyIncrement(payload.Value, inc)
+		if err != nil {
+			return nil, err
+		}
+		if inc.Validate != nil {
+			if err := inc.Validate(payload.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		rec, err := tt.toRecord(payload)
+		if err != nil {
+			return nil, ErrUpdateOperationFail
+		}
+
+		request := tt.auditedWrite(ctx, inc.Key, payload.Value, "kv_swap_value", inc.Key, optionalString(old.KeyID), old.Value, rec.Value, optionalString(rec.Codec), optionalString(rec.KeyID))
+
+		var swapped []bool
+		if err := tt.conn.Do(request).GetTyped(&swapped); err != nil {
+			return nil, ErrUpdateOperationFail
+		}
+		if len(swapped) == 0 || !swapped[0] {
+			continue
+		}
+
+		// kv_swap_value leaves the old projection in place.
+		rec.Attrs = old.Attrs
+		if _, err := tt.refreshAttrs(ctx, rec, payload.Value); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+func (tt Tarantool) selectRecord(ctx context.Context, key string) (record, error) {
+	var result []record
+	if err := tt.conn.Do(tt.getRequest(ctx, key)).GetTyped(&result); err != nil {
+		return record{}, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return record{}, ErrNotFound
+	}
+
+	return result[0], nil
+}
+
+// applyIncrement mirrors `kv_incr` on a decoded value: missing maps on the
+// path are created and a missing number starts from zero.
+func applyIncrement(value map[string]any, inc domain.Increment) (any, error) {
+	parent := value
+	for _, segment := range inc.Path[:len(inc.Path)-1] {
+		switch child := parent[segment].(type) {
+		case map[string]any:
+			parent = child
+		case nil:
+			next := make(map[string]any)
+			parent[segment] = next
+			parent = next
+		default:
+			return nil, ErrNotANumber
+		}
+	}
+
+	last := inc.Path[len(inc.Path)-1]
+	var current any = int64(0)
+	if parent[last] != nil {
+		current = parent[last]
+	}
+
+	result, ok := addNumber(current, inc.Delta)
+	if !ok {
+		return nil, ErrNotANumber
+	}
+
+	number, _ := toFloat(result)
+	if (inc.Min != nil && number < *inc.Min) || (inc.Max != nil && number > *inc.Max) {
+		return nil, ErrOutOfBounds
+	}
+
+	parent[last] = result
+	return result, nil
+}
+
+// addNumber keeps integers integral when the delta is a whole number.
+func addNumber(current any, delta float64) (any, bool) {
+	if n, ok := toInt64(current); ok && isWhole(delta) {
+		return n + int64(delta), true
+	}
+
+	f, ok := toFloat(current)
+	if !ok {
+		return nil, false
+	}
+	return f + delta, true
+}
+
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), v <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+func isWhole(f float64) bool {
+	return f == math.Trunc(f) && math.Abs(f) < math.MaxInt64
+}
+
+// numberArg sends whole numbers as integers, so Tarantool keeps integer
+// counters integral.
+func numberArg(f float64) any {
+	if isWhole(f) {
+		return int64(f)
+	}
+	return f
+}
+
+func optionalNumber(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return numberArg(*f)
+}
@@ -0,0 +1,174 @@
+// Application-level encryption of stored values.
+
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"tarantool-app/config"
+	"time"
+)
+
+var ErrUnknownKeyID = errors.New("unknown encryption key id")
+
+// keyringFile is the on-disk format of the keyring:
+//
+//	{"active": "2025-01", "keys": {"2024-06": "<base64>", "2025-01": "<base64>"}}
+//
+// Keys are base64-encoded 16, 24 or 32 byte AES keys.
+type keyringFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+type keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+func loadKeyring(path string) (keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keyring{}, err
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return keyring{}, fmt.Errorf("keyring %q: %w", path, err)
+	}
+
+	ring := keyring{active: file.Active, aeads: make(map[string]cipher.AEAD, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return keyring{}, fmt.Errorf("keyring %q: key %q: %w", path, id, err)
+		}
+		block, err := aes.NewCipher(secret)
+		if err != nil {
+			return keyring{}, fmt.Errorf("keyring %q: key %q: %w", path, id, err)
+		}
+		if ring.aeads[id], err = cipher.NewGCM(block); err != nil {
+			return keyring{}, err
+		}
+	}
+
+	if _, ok := ring.aeads[ring.active]; !ok {
+		return keyring{}, fmt.Errorf("keyring %q: active key %q is not defined", path, ring.active)
+	}
+
+	return ring, nil
+}
+
+// valueCipher encrypts values with AES-GCM, binding each ciphertext to its
+// key as additional data. The keyring can be reloaded while serving.
+type valueCipher struct {
+	enabled  bool
+	path     string
+	prefixes []string
+
+	mu      sync.RWMutex
+	ring    keyring
+	modTime time.Time
+}
+
+func newValueCipher(cfg config.EncryptionConfig) (*valueCipher, error) {
+	vc := &valueCipher{enabled: cfg.Enabled, path: cfg.KeyringFile, prefixes: cfg.Prefixes}
+	if !vc.enabled {
+		return vc, nil
+	}
+
+	// modTime stays unset, so the first reload reports the keyring as
+	// changed and key rotation starts a pass after every start.
+	ring, err := loadKeyring(vc.path)
+	if err != nil {
+		return nil, err
+	}
+	vc.ring = ring
+	return vc, nil
+}
+
+// reload reads the keyring file again if it was modified since the last load.
+func (vc *valueCipher) reload() (bool, error) {
+	info, err := os.Stat(vc.path)
+	if err != nil {
+		return false, err
+	}
+
+	vc.mu.RLock()
+	unchanged := info.ModTime().Equal(vc.modTime)
+	vc.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	ring, err := loadKeyring(vc.path)
+	if err != nil {
+		return false, err
+	}
+
+	vc.mu.Lock()
+	vc.ring, vc.modTime = ring, info.ModTime()
+	vc.mu.Unlock()
+
+	return true, nil
+}
+
+func (vc *valueCipher) activeKeyID() string {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	return vc.ring.active
+}
+
+func (vc *valueCipher) appliesTo(key string) bool {
+	if !vc.enabled {
+		return false
+	}
+	if len(vc.prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range vc.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypt seals plaintext with the active key and returns the key id used.
+func (vc *valueCipher) encrypt(key string, plaintext []byte) ([]byte, string, error) {
+	vc.mu.RLock()
+	id := vc.ring.active
+	aead := vc.ring.aeads[id]
+	vc.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(key)), id, nil
+}
+
+func (vc *valueCipher) decrypt(key, id string, ciphertext []byte) ([]byte, error) {
+	vc.mu.RLock()
+	aead, ok := vc.ring.aeads[id]
+	vc.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, id)
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(key))
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"tarantool-app/config"
+	"testing"
+	"time"
+)
+
+func TestValueCipher(t *testing.T) {
+	path := writeKeyring(t, "2025-01", "2024-06", "2025-01")
+	vc, err := newValueCipher(config.EncryptionConfig{Enabled: true, KeyringFile: path})
+	if err != nil {
+		t.Fatalf("newValueCipher: %v", err)
+	}
+
+	plaintext := []byte("secret value")
+	ciphertext, kid, err := vc.encrypt("users/1", plaintext)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if kid != "2025-01" {
+		t.Fatalf("sealed with %q, want the active key", kid)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		key        string
+		kid        string
+		ciphertext []byte
+		err        error // nil for any error
+		ok         bool
+	}{
+		{"same key", "users/1", kid, ciphertext, nil, true},
+		{"other tuple key", "users/2", kid, ciphertext, nil, false},
+		{"other known kid", "users/1", "2024-06", ciphertext, nil, false},
+		{"unknown kid", "users/1", "2023-01", ciphertext, ErrUnknownKeyID, false},
+		{"tampered", "users/1", kid, tampered, nil, false},
+		{"too short", "users/1", kid, ciphertext[:4], nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, err := vc.decrypt(tt.key, tt.kid, tt.ciphertext)
+			if tt.ok {
+				if err != nil || !bytes.Equal(opened, plaintext) {
+					t.Fatalf("decrypt = %q, %v, want %q", opened, err, plaintext)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("decrypt = %q, want an error", opened)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("decrypt error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValueCipherReload(t *testing.T) {
+	path := writeKeyring(t, "2024-06", "2024-06")
+	vc, err := newValueCipher(config.EncryptionConfig{Enabled: true, KeyringFile: path})
+	if err != nil {
+		t.Fatalf("newValueCipher: %v", err)
+	}
+	old, _, err := vc.encrypt("k", []byte("v"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	if changed, err := vc.reload(); err != nil || !changed {
+		t.Fatalf("first reload = %v, %v, want the keyring loaded at start reported", changed, err)
+	}
+	if changed, err := vc.reload(); err != nil || changed {
+		t.Fatalf("reload of an unmodified keyring = %v, %v, want unchanged", changed, err)
+	}
+
+	// Rotate to a new active key, keeping the retired one.
+	writeKeyringTo(t, path, "2025-01", "2024-06", "2025-01")
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := vc.reload(); err != nil || !changed {
+		t.Fatalf("reload of a modified keyring = %v, %v, want changed", changed, err)
+	}
+	if kid := vc.activeKeyID(); kid != "2025-01" {
+		t.Fatalf("active key %q after reload, want 2025-01", kid)
+	}
+	if _, err := vc.decrypt("k", "2024-06", old); err != nil {
+		t.Fatalf("decrypt with the retired key: %v", err)
+	}
+}
+
+func TestLoadKeyringErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not json", "{"},
+		{"active key not defined", `{"active": "b", "keys": {"a": "` + testKey(1) + `"}}`},
+		{"not base64", `{"active": "a", "keys": {"a": "%%%"}}`},
+		{"bad key size", `{"active": "a", "keys": {"a": "` + base64.StdEncoding.EncodeToString([]byte("short")) + `"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keyring.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadKeyring(path); err == nil {
+				t.Fatal("loadKeyring succeeded, want an error")
+			}
+		})
+	}
+}
+
+// writeKeyring writes a keyring of the given key ids, each with a key of
+// its own, to a temporary file and returns its path.
+func writeKeyring(t *testing.T, active string, ids ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	writeKeyringTo(t, path, active, ids...)
+	return path
+}
+
+func writeKeyringTo(t *testing.T, path, active string, ids ...string) {
+	t.Helper()
+	file := keyringFile{Active: active, Keys: make(map[string]string, len(ids))}
+	for i, id := range ids {
+		file.Keys[id] = testKey(byte(i + 1))
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testKey returns a base64 AES-256 key filled with b.
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
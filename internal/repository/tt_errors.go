@@ -23,4 +23,21 @@ var (
 	ErrSelectOperationFail = NewRepositoryError("select operation failed")
 	ErrUpdateOperationFail = NewRepositoryError("update operation failed")
 	ErrDeleteOperationFail = NewRepositoryError("delete operation failed")
+	ErrCommitOperationFail = NewRepositoryError("commit operation failed")
+	ErrBlobIncomplete      = NewRepositoryError("409 blob upload is incomplete")
+	ErrSQLStatementFail    = NewRepositoryError("400 SQL statement failed")
+	ErrNotANumber          = NewRepositoryError("409 value at path is not a number")
+	ErrOutOfBounds         = NewRepositoryError("409 counter would leave its bounds")
+	ErrLockHeld            = NewRepositoryError("409 lock is held by another owner")
+	ErrLockNotHeld         = NewRepositoryError("409 lock is not held")
+	ErrQueueEmpty          = NewRepositoryError("queue is empty")
+	ErrTaskNotFound        = NewRepositoryError("404 task not found")
+	ErrTaskNotTaken        = NewRepositoryError("409 task is not taken")
+	ErrTaskTakenAgain      = NewRepositoryError("409 task was taken again, the receipt is stale")
+	ErrMigrationFail       = NewRepositoryError("migration failed")
+	ErrVersionNotFound     = NewRepositoryError("404 version not found")
+	ErrVersionDeleted      = NewRepositoryError("410 key is deleted in this version")
+	ErrHistoryDisabled     = NewRepositoryError("409 history is not enabled")
+	ErrNotInTrash          = NewRepositoryError("404 key is not in the trash")
+	ErrCollectionExists    = NewRepositoryError("409 collection already exists")
 )
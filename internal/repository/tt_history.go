@@ -0,0 +1,188 @@
+// Versions of stored values kept in `kv_history`.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error codes raised by `kv_audited` restoring a version.
+const (
+	errCodeVersionNotFound iproto.Error = 10011
+	errCodeVersionDeleted  iproto.Error = 10012
+)
+
+var _ interfaces.HistoryRepository = Tarantool{} // Tarantool must satisfy HistoryRepository
+
+// historyPolicy holds the number and maximum age of versions kept per key
+// prefix. A nil policy keeps no versions.
+type historyPolicy struct {
+	versions int
+	maxAge   int64 // milliseconds
+	rules    []config.HistoryRule
+}
+
+func newHistoryPolicy(cfg config.HistoryConfig) *historyPolicy {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &historyPolicy{versions: cfg.Versions, maxAge: cfg.MaxAge.Milliseconds(), rules: cfg.Rules}
+}
+
+// forKey returns the `kv_audited` argument for changes of key, the policy
+// of the longest matching prefix rule with zero settings inherited.
+func (hp *historyPolicy) forKey(key string) any {
+	if hp == nil {
+		return nil
+	}
+
+	versions, maxAge, matched := hp.versions, hp.maxAge, -1
+	for _, rule := range hp.rules {
+		if !strings.HasPrefix(key, rule.Prefix) || len(rule.Prefix) <= matched {
+			continue
+		}
+		versions, maxAge, matched = hp.versions, hp.maxAge, len(rule.Prefix)
+		if rule.Versions > 0 {
+			versions = rule.Versions
+		}
+		if rule.MaxAge > 0 {
+			maxAge = rule.MaxAge.Milliseconds()
+		}
+	}
+	return map[string]any{"versions": versions, "max_age_ms": maxAge}
+}
+
+// versionRecord is a tuple of `kv_history`. A deleted key leaves a
+// version with a nil value.
+type versionRecord struct {
+	_msgpack  struct{} `msgpack:",as_array"` //nolint:unused
+	Key       string
+	Version   uint64
+	At        int64
+	ExpiresAt int64
+	Value     any
+	Codec     string
+	KeyID     string
+	Attrs     map[string]any
+}
+
+// toVersion restores the plain value of a version.
+func (tt Tarantool) toVersion(ctx context.Context, vr versionRecord) (domain.Version, error) {
+	version := domain.Version{Key: vr.Key, Version: vr.Version, At: vr.At, Deleted: vr.Value == nil}
+	if version.Deleted {
+		return version, nil
+	}
+
+	payload, err := tt.toPayload(record{Key: vr.Key, Value: vr.Value, Codec: vr.Codec, KeyID: vr.KeyID})
+	if err != nil {
+		utils.LoggerFromContext(ctx, tt.log).Error("Failed to decode stored version",
+			"key", vr.Key,
+			"version", vr.Version,
+			"error", err,
+		)
+		return domain.Version{}, ErrSelectOperationFail
+	}
+	version.Value = payload.Value
+	return version, nil
+}
+
+// SelectVersions returns up to limit versions of key older than before,
+// or the newest ones when before is zero, newest first.
+func (tt Tarantool) SelectVersions(ctx context.Context, key string, before uint64, limit int) ([]domain.Version, error) {
+	iterator, start := tarantool.IterLe, []any{key}
+	if before > 0 {
+		iterator, start = tarantool.IterLt, []any{key, before}
+	}
+
+	request := tarantool.NewSelectRequest("kv_history").
+		Iterator(iterator).
+		Key(start).
+		Limit(uint32(limit)).
+		Context(ctx)
+
+	var result []versionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	versions := make([]domain.Version, 0, len(result))
+	for _, vr := range result {
+		if vr.Key != key {
+			break
+		}
+		version, err := tt.toVersion(ctx, vr)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// SelectVersion returns the given version of key.
+func (tt Tarantool) SelectVersion(ctx context.Context, key string, version uint64) (domain.Version, error) {
+	request := tarantool.NewSelectRequest("kv_history").
+		Key([]any{key, version}).
+		Context(ctx)
+
+	var result []versionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.Version{}, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.Version{}, ErrVersionNotFound
+	}
+
+	return tt.toVersion(ctx, result[0])
+}
+
+// RestoreVersion makes the given version of key its current value and
+// returns the version recorded for it.
+func (tt Tarantool) RestoreVersion(ctx context.Context, key string, version uint64) (domain.Version, error) {
+	if tt.history == nil {
+		return domain.Version{}, ErrHistoryDisabled
+	}
+
+	// The version keeps the projection of the indexes declared back then,
+	// the restored value is projected on the current ones.
+	old, err := tt.SelectVersion(ctx, key, version)
+	if err != nil {
+		return domain.Version{}, err
+	}
+	if old.Deleted {
+		return domain.Version{}, ErrVersionDeleted
+	}
+
+	request := tt.auditedWrite(ctx, key, old.Value, "restore", key, version, extractAttrs(tt.indexes, old.Value))
+
+	var result []versionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			switch tntErr.Code {
+			case errCodeVersionNotFound:
+				return domain.Version{}, ErrVersionNotFound
+			case errCodeVersionDeleted:
+				return domain.Version{}, ErrVersionDeleted
+			}
+		}
+		return domain.Version{}, ErrUpdateOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.Version{}, ErrUpdateOperationFail
+	}
+
+	return tt.toVersion(ctx, result[0])
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"reflect"
+	"tarantool-app/config"
+	"testing"
+	"time"
+)
+
+func TestHistoryPolicyForKey(t *testing.T) {
+	policy := newHistoryPolicy(config.HistoryConfig{
+		Enabled:  true,
+		Versions: 10,
+		MaxAge:   time.Hour,
+		Rules: []config.HistoryRule{
+			{Prefix: "orders/", Versions: 50},
+			{Prefix: "orders/archive/", MaxAge: 24 * time.Hour},
+			{Prefix: "sessions/", Versions: 1, MaxAge: time.Minute},
+			{Prefix: "sessions/", Versions: 3},
+		},
+	})
+
+	tests := []struct {
+		name         string
+		key          string
+		versions     int
+		maxAgeMillis int64
+	}{
+		{"no rule", "users/1", 10, time.Hour.Milliseconds()},
+		{"matching rule", "orders/1", 50, time.Hour.Milliseconds()},
+		{"longest prefix wins, zero settings inherited from the defaults", "orders/archive/1", 10, (24 * time.Hour).Milliseconds()},
+		{"first of equal prefixes wins", "sessions/abc", 1, time.Minute.Milliseconds()},
+		{"prefix is not a substring match", "my/orders/1", 10, time.Hour.Milliseconds()},
+		{"empty key", "", 10, time.Hour.Milliseconds()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[string]any{"versions": tt.versions, "max_age_ms": tt.maxAgeMillis}
+			if got := policy.forKey(tt.key); !reflect.DeepEqual(got, want) {
+				t.Fatalf("forKey(%q) = %v, want %v", tt.key, got, want)
+			}
+		})
+	}
+}
+
+func TestHistoryPolicyDisabled(t *testing.T) {
+	policy := newHistoryPolicy(config.HistoryConfig{Enabled: false, Versions: 10, MaxAge: time.Hour})
+	if got := policy.forKey("users/1"); got != nil {
+		t.Fatalf("forKey with history disabled = %v, want nil", got)
+	}
+}
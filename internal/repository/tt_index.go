@@ -0,0 +1,313 @@
+// Secondary indexes on paths inside stored values.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+
+	"github.com/tarantool/go-tarantool/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const multikeySuffix = "[*]"
+
+// indexedPath is a parsed `indexes` entry. Indexes are built by Tarantool
+// on the `attrs` field, a plain projection of the indexed paths of the
+// value, so they work for compressed and encrypted values as well.
+type indexedPath struct {
+	name     string
+	segments []string
+	multikey bool
+	kind     string
+}
+
+func parseIndexes(indexes []config.IndexConfig) ([]indexedPath, error) {
+	paths := make([]indexedPath, 0, len(indexes))
+	for _, index := range indexes {
+		path, multikey := strings.CutSuffix(index.Path, multikeySuffix)
+		segments := strings.Split(path, ".")
+		for _, segment := range segments {
+			if segment == "" || strings.ContainsAny(segment, "[]*") {
+				return nil, fmt.Errorf("index %q: invalid path %q", index.Name, index.Path)
+			}
+		}
+
+		switch index.Type {
+		case "string", "unsigned", "integer", "number", "boolean":
+		default:
+			return nil, fmt.Errorf("index %q: unsupported type %q", index.Name, index.Type)
+		}
+
+		paths = append(paths, indexedPath{
+			name:     index.Name,
+			segments: segments,
+			multikey: multikey,
+			kind:     index.Type,
+		})
+	}
+	return paths, nil
+}
+
+// extractAttrs copies the indexed paths of value into a map of the same
+// shape. Values whose type does not match the index are left out, so they
+// are not indexed instead of failing the write.
+func extractAttrs(paths []indexedPath, value map[string]any) map[string]any {
+	var attrs map[string]any
+
+	for _, path := range paths {
+		found, ok := lookupPath(value, path.segments)
+		if !ok {
+			continue
+		}
+
+		var converted any
+		if path.multikey {
+			items, isArray := found.([]any)
+			if !isArray {
+				continue
+			}
+			elements := make([]any, 0, len(items))
+			for _, item := range items {
+				if element, ok := convertIndexValue(path.kind, item); ok {
+					elements = append(elements, element)
+				}
+			}
+			converted = elements
+		} else if converted, ok = convertIndexValue(path.kind, found); !ok {
+			continue
+		}
+
+		if attrs == nil {
+			attrs = make(map[string]any)
+		}
+		storePath(attrs, path.segments, converted)
+	}
+
+	return attrs
+}
+
+func lookupPath(value map[string]any, segments []string) (any, bool) {
+	var current any = value
+	for _, segment := range segments {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+func storePath(attrs map[string]any, segments []string, value any) {
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := attrs[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			attrs[segment] = next
+		}
+		attrs = next
+	}
+	attrs[segments[len(segments)-1]] = value
+}
+
+// convertIndexValue normalizes numbers decoded from JSON, msgpack or CBOR
+// into the representation expected by the index type.
+func convertIndexValue(kind string, value any) (any, bool) {
+	switch kind {
+	case "string":
+		s, ok := value.(string)
+		return s, ok
+	case "boolean":
+		b, ok := value.(bool)
+		return b, ok
+	}
+
+	number, ok := toFloat(value)
+	if !ok {
+		return nil, false
+	}
+
+	switch kind {
+	case "unsigned":
+		if number < 0 || number != math.Trunc(number) {
+			return nil, false
+		}
+		return uint64(number), true
+	case "integer":
+		if number != math.Trunc(number) {
+			return nil, false
+		}
+		return int64(number), true
+	default:
+		return number, true
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+var _ interfaces.QueryRepository = Tarantool{} // Tarantool must satisfy QueryRepository
+
+// SelectByIndex returns one page of values whose index key equals q.Key.
+func (tt Tarantool) SelectByIndex(ctx context.Context, q domain.Query) (domain.Page, error) {
+	request := tarantool.NewSelectRequest(defaultSpace).
+		Index(q.Index).
+		Iterator(tarantool.IterEq).
+		Key([]any{q.Key}).
+		Limit(uint32(q.Limit)).
+		FetchPos(true).
+		Context(ctx)
+	if len(q.After) > 0 {
+		request = request.After(q.After)
+	}
+
+	response, err := tt.conn.Do(request).GetResponse()
+	if err != nil {
+		return domain.Page{}, ErrSelectOperationFail
+	}
+
+	var result []record
+	if err := response.DecodeTyped(&result); err != nil {
+		return domain.Page{}, ErrSelectOperationFail
+	}
+
+	page := domain.Page{Items: make([]domain.Payload, 0, len(result))}
+	for _, rec := range result {
+		payload, err := tt.toPayload(rec)
+		if err != nil {
+			return domain.Page{}, err
+		}
+		page.Items = append(page.Items, payload)
+	}
+
+	if selectResponse, ok := response.(*tarantool.SelectResponse); ok && len(result) == q.Limit {
+		if page.Next, err = selectResponse.Pos(); err != nil {
+			return domain.Page{}, ErrSelectOperationFail
+		}
+	}
+
+	return page, nil
+}
+
+// Reindex fills the `attrs` field of tuples written before an index was
+// declared. Tuples modified concurrently are skipped, and so are values
+// that cannot be decoded, which are logged.
+func (tt Tarantool) Reindex(ctx context.Context, batchSize int) (int, error) {
+	if len(tt.indexes) == 0 {
+		return 0, nil
+	}
+
+	updated := 0
+	for after := ""; ; {
+		batch, err := tt.scanRecords(ctx, after, batchSize)
+		if err != nil {
+			return updated, err
+		}
+
+		for _, rec := range batch {
+			payload, err := tt.toPayload(rec)
+			if err != nil {
+				utils.LoggerFromContext(ctx, tt.log).Warn("Failed to decode stored value, not reindexed",
+					"key", rec.Key,
+					"error", err,
+				)
+				continue
+			}
+
+			refreshed, err := tt.refreshAttrs(ctx, rec, payload.Value)
+			if err != nil {
+				return updated, err
+			}
+			if refreshed {
+				updated++
+			}
+		}
+
+		if len(batch) < batchSize {
+			return updated, nil
+		}
+		after = batch[len(batch)-1].Key
+	}
+}
+
+// refreshAttrs stores the indexed projection of value, the decoded value of
+// rec, unless it is up to date or the value changed since rec was read.
+func (tt Tarantool) refreshAttrs(ctx context.Context, rec record, value map[string]any) (bool, error) {
+	if len(tt.indexes) == 0 {
+		return false, nil
+	}
+
+	attrs := extractAttrs(tt.indexes, value)
+	if sameAttrs(rec.Attrs, attrs) {
+		return false, nil
+	}
+
+	request := tarantool.NewCallRequest("kv_set_attrs").
+		Args([]any{rec.Key, rec.Value, attrs}).
+		Context(ctx)
+
+	var result []bool
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return false, ErrUpdateOperationFail
+	}
+	return len(result) > 0 && result[0], nil
+}
+
+func sameAttrs(stored, extracted map[string]any) bool {
+	if len(stored) == 0 || len(extracted) == 0 {
+		return len(stored) == len(extracted)
+	}
+
+	a, errA := marshalCompact(stored)
+	b, errB := marshalCompact(extracted)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+func marshalCompact(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
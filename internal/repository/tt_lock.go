@@ -0,0 +1,62 @@
+// Leases for distributed locks.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"time"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error codes raised by the `kv_lock_*` functions.
+const (
+	errCodeLockHeld    iproto.Error = 10005
+	errCodeLockNotHeld iproto.Error = 10006
+)
+
+var _ interfaces.LockRepository = Tarantool{} // Tarantool must satisfy LockRepository
+
+func (tt Tarantool) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (domain.Lock, error) {
+	return tt.callLock(ctx, "kv_lock_acquire", name, owner, ttl.Milliseconds())
+}
+
+func (tt Tarantool) RenewLock(ctx context.Context, name, owner string, token uint64, ttl time.Duration) (domain.Lock, error) {
+	return tt.callLock(ctx, "kv_lock_renew", name, owner, token, ttl.Milliseconds())
+}
+
+func (tt Tarantool) ReleaseLock(ctx context.Context, name, owner string, token uint64) (domain.Lock, error) {
+	return tt.callLock(ctx, "kv_lock_release", name, owner, token)
+}
+
+// callLock calls a lock function that returns the affected lock. Expiry
+// is checked by Tarantool against its own clock.
+func (tt Tarantool) callLock(ctx context.Context, function string, args ...any) (domain.Lock, error) {
+	request := tarantool.NewCallRequest(function).
+		Args(args).
+		Context(ctx)
+
+	var result []domain.Lock
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			switch tntErr.Code {
+			case errCodeLockHeld:
+				return domain.Lock{}, ErrLockHeld
+			case errCodeLockNotHeld:
+				return domain.Lock{}, ErrLockNotHeld
+			}
+		}
+		return domain.Lock{}, ErrUpdateOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.Lock{}, ErrUpdateOperationFail
+	}
+
+	return result[0], nil
+}
@@ -0,0 +1,61 @@
+// Versioned schema migrations run by Tarantool.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// RequiredSchemaVersion is the migration this version of the application
+// relies on. Bump it together with new steps in `migrations.lua`.
+const RequiredSchemaVersion = 13
+
+var _ interfaces.MigrationRepository = Tarantool{} // Tarantool must satisfy MigrationRepository
+
+func (tt Tarantool) MigrationStatus(ctx context.Context) ([]domain.Migration, error) {
+	return tt.callMigrations(ctx, "kv_migrations_status")
+}
+
+// MigrateUp applies pending migrations up to target, every one when
+// target is zero.
+func (tt Tarantool) MigrateUp(ctx context.Context, target uint64) ([]domain.Migration, error) {
+	if target == 0 {
+		return tt.callMigrations(ctx, "kv_migrate_up", nil)
+	}
+	return tt.callMigrations(ctx, "kv_migrate_up", target)
+}
+
+// MigrateDown reverts applied migrations newer than target.
+func (tt Tarantool) MigrateDown(ctx context.Context, target uint64) ([]domain.Migration, error) {
+	return tt.callMigrations(ctx, "kv_migrate_down", target)
+}
+
+func (tt Tarantool) callMigrations(ctx context.Context, function string, args ...any) ([]domain.Migration, error) {
+	if args == nil {
+		args = []any{}
+	}
+
+	request := tarantool.NewCallRequest(function).
+		Args(args).
+		Context(ctx)
+
+	var result [][]domain.Migration
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationFail, tntErr.Msg)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrMigrationFail, err)
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
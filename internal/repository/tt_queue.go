@@ -0,0 +1,114 @@
+// Work queues persisted in the `kv_queue_tasks` space.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"time"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error codes raised by the `kv_queue_*` functions.
+const (
+	errCodeTaskNotFound iproto.Error = 10007
+	errCodeTaskNotTaken iproto.Error = 10008
+	errCodeTaskReceipt  iproto.Error = 10016
+)
+
+// takeWaitSlice bounds a single blocking `kv_queue_take` call. Cancelling
+// the context does not interrupt the call on the server, so a long-polling
+// take stops waiting at most one slice after its client went away.
+const takeWaitSlice = 500 * time.Millisecond
+
+var _ interfaces.QueueRepository = Tarantool{} // Tarantool must satisfy QueueRepository
+
+func (tt Tarantool) PutTask(ctx context.Context, queue string, payload any, delay time.Duration) (domain.Task, error) {
+	task, err := tt.callQueue(ctx, "kv_queue_put", queue, payload, delay.Milliseconds())
+	if errors.Is(err, ErrUpdateOperationFail) {
+		return domain.Task{}, ErrInsertOperationFail
+	}
+	return task, err
+}
+
+// TakeTask long-polls for a due task in slices. It returns ErrQueueEmpty
+// once wait is over.
+func (tt Tarantool) TakeTask(ctx context.Context, queue string, visibility time.Duration, maxAttempts int, wait time.Duration) (domain.Task, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		slice := min(time.Until(deadline), takeWaitSlice)
+		task, err := tt.callQueue(ctx, "kv_queue_take", queue, visibility.Milliseconds(), maxAttempts, max(slice, 0).Milliseconds())
+		if !errors.Is(err, ErrQueueEmpty) || time.Now().After(deadline) {
+			return task, err
+		}
+		if err := ctx.Err(); err != nil {
+			return domain.Task{}, err
+		}
+	}
+}
+
+// AckTask, NackTask and BuryTask act on a task taken with the receipt,
+// the attempt number returned by the take, and fail with
+// ErrTaskTakenAgain once another take superseded it.
+func (tt Tarantool) AckTask(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error) {
+	return tt.callQueue(ctx, "kv_queue_ack", queue, id, receipt)
+}
+
+func (tt Tarantool) NackTask(ctx context.Context, queue string, id, receipt uint64, delay time.Duration, maxAttempts int) (domain.Task, error) {
+	return tt.callQueue(ctx, "kv_queue_nack", queue, id, receipt, delay.Milliseconds(), maxAttempts)
+}
+
+func (tt Tarantool) BuryTask(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error) {
+	return tt.callQueue(ctx, "kv_queue_bury", queue, id, receipt)
+}
+
+// PeekTasks lists tasks with the status in the order they become due,
+// without taking them.
+func (tt Tarantool) PeekTasks(ctx context.Context, queue, status string, limit int) ([]domain.Task, error) {
+	request := tarantool.NewSelectRequest("kv_queue_tasks").
+		Index("queue_status").
+		Iterator(tarantool.IterEq).
+		Key([]any{queue, status}).
+		Limit(uint32(limit)).
+		Context(ctx)
+
+	var result []domain.Task
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	return result, nil
+}
+
+// callQueue calls a queue function that returns a task or nil.
+func (tt Tarantool) callQueue(ctx context.Context, function string, args ...any) (domain.Task, error) {
+	request := tarantool.NewCallRequest(function).
+		Args(args).
+		Context(ctx)
+
+	var result []*domain.Task
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			switch tntErr.Code {
+			case errCodeTaskNotFound:
+				return domain.Task{}, ErrTaskNotFound
+			case errCodeTaskNotTaken:
+				return domain.Task{}, ErrTaskNotTaken
+			case errCodeTaskReceipt:
+				return domain.Task{}, ErrTaskTakenAgain
+			}
+		}
+		return domain.Task{}, ErrUpdateOperationFail
+	}
+
+	if len(result) == 0 || result[0] == nil {
+		return domain.Task{}, ErrQueueEmpty
+	}
+
+	return *result[0], nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"fmt"
+	"tarantool-app/internal/domain"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Number of `kv_storage` fields written by this version of the application.
+const recordFields = 5
+
+// Field numbers of the `kv_storage` space format.
+const (
+	fieldKey = iota
+	fieldValue
+	fieldCodec
+	fieldKeyID
+	fieldAttrs
+)
+
+// record is the stored form of a payload. Value holds either a plain map
+// or, when Codec is set, the encoded msgpack of that map. When KeyID is set
+// the encoded value is additionally encrypted with that key. Attrs holds
+// the plain indexed paths of the value.
+type record struct {
+	Key   string
+	Value any
+	Codec string
+	KeyID string
+	Attrs map[string]any
+}
+
+func (r *record) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(recordFields); err != nil {
+		return err
+	}
+	if err := e.EncodeString(r.Key); err != nil {
+		return err
+	}
+	if err := e.Encode(r.Value); err != nil {
+		return err
+	}
+	if err := encodeOptionalString(e, r.Codec); err != nil {
+		return err
+	}
+	if err := encodeOptionalString(e, r.KeyID); err != nil {
+		return err
+	}
+	if len(r.Attrs) == 0 {
+		return e.EncodeNil()
+	}
+	return e.EncodeMap(r.Attrs)
+}
+
+// DecodeMsgpack accepts tuples written before the optional fields were added.
+func (r *record) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+	if length < fieldCodec {
+		return fmt.Errorf("array len doesn't match: %d", length)
+	}
+
+	if r.Key, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if r.Value, err = d.DecodeInterface(); err != nil {
+		return err
+	}
+	if length > fieldCodec {
+		if r.Codec, err = decodeOptionalString(d); err != nil {
+			return err
+		}
+	}
+	if length > fieldKeyID {
+		if r.KeyID, err = decodeOptionalString(d); err != nil {
+			return err
+		}
+	}
+	if length > fieldAttrs {
+		if r.Attrs, err = d.DecodeMap(); err != nil {
+			return err
+		}
+	}
+
+	for range length - min(length, recordFields) {
+		if err := d.Skip(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeOptionalString(e *msgpack.Encoder, s string) error {
+	if s == "" {
+		return e.EncodeNil()
+	}
+	return e.EncodeString(s)
+}
+
+// optionalString maps an empty string to nil for nullable fields.
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// optionalMap maps an empty map to nil for nullable fields.
+func optionalMap(m map[string]any) any {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func decodeOptionalString(d *msgpack.Decoder) (string, error) {
+	s, err := d.DecodeInterface()
+	if err != nil || s == nil {
+		return "", err
+	}
+	if str, ok := s.(string); ok {
+		return str, nil
+	}
+	return "", fmt.Errorf("unexpected field type %T", s)
+}
+
+// toRecord encodes the payload value according to the compression and
+// encryption rules.
+func (tt Tarantool) toRecord(p domain.Payload) (record, error) {
+	value, codec, err := tt.codec.encode(p.Key, p.Value, p.Raw)
+	if err != nil {
+		return record{}, err
+	}
+
+	rec := record{Key: p.Key, Value: value, Codec: codec, Attrs: extractAttrs(tt.indexes, p.Value)}
+	if tt.cipher.appliesTo(p.Key) {
+		return tt.seal(rec)
+	}
+	return rec, nil
+}
+
+// seal encrypts the encoded value of a record with the active key.
+func (tt Tarantool) seal(rec record) (record, error) {
+	plaintext, ok := rec.Value.([]byte)
+	if rec.Codec == "" {
+		raw, isRaw := rec.Value.(msgpack.RawMessage)
+		if !isRaw {
+			var err error
+			if raw, err = msgpack.Marshal(rec.Value); err != nil {
+				return record{}, err
+			}
+		}
+		plaintext, rec.Codec, ok = raw, CodecMsgpack, true
+	}
+	if !ok {
+		return record{}, fmt.Errorf("unexpected encoded value type %T", rec.Value)
+	}
+
+	ciphertext, keyID, err := tt.cipher.encrypt(rec.Key, plaintext)
+	if err != nil {
+		return record{}, err
+	}
+
+	rec.Value, rec.KeyID = ciphertext, keyID
+	return rec, nil
+}
+
+// open decrypts the value of an encrypted record, leaving it encoded.
+func (tt Tarantool) open(rec record) (record, error) {
+	if rec.KeyID == "" {
+		return rec, nil
+	}
+
+	ciphertext, ok := rec.Value.([]byte)
+	if !ok {
+		return record{}, fmt.Errorf("unexpected encrypted value type %T", rec.Value)
+	}
+
+	plaintext, err := tt.cipher.decrypt(rec.Key, rec.KeyID, ciphertext)
+	if err != nil {
+		return record{}, err
+	}
+
+	rec.Value, rec.KeyID = plaintext, ""
+	return rec, nil
+}
+
+// toPayload restores the plain value of a stored record.
+func (tt Tarantool) toPayload(r record) (domain.Payload, error) {
+	r, err := tt.open(r)
+	if err != nil {
+		return domain.Payload{}, err
+	}
+
+	value, err := tt.codec.decode(r.Value, r.Codec)
+	if err != nil {
+		return domain.Payload{}, err
+	}
+	return domain.Payload{Key: r.Key, Value: value}, nil
+}
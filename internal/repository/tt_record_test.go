@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	path := writeKeyring(t, "2025-01", "2025-01")
+	small := map[string]any{"name": "Ann"}
+	large := map[string]any{"text": strings.Repeat("compressible ", 100)}
+
+	tests := []struct {
+		name        string
+		compression config.CompressionConfig
+		encryption  config.EncryptionConfig
+		key         string
+		value       map[string]any
+		codec       string
+		kid         string
+	}{
+		{"plain", config.CompressionConfig{}, config.EncryptionConfig{}, "users/1", small, "", ""},
+		{"compressed", config.CompressionConfig{Enabled: true, Algorithm: CodecZstd, Threshold: 64}, config.EncryptionConfig{}, "users/1", large, CodecZstd, ""},
+		{"encrypted", config.CompressionConfig{}, config.EncryptionConfig{Enabled: true, KeyringFile: path}, "users/1", small, CodecMsgpack, "2025-01"},
+		{"compressed and encrypted", config.CompressionConfig{Enabled: true, Algorithm: CodecSnappy, Threshold: 64}, config.EncryptionConfig{Enabled: true, KeyringFile: path}, "users/1", large, CodecSnappy, "2025-01"},
+		{"outside encryption prefixes", config.CompressionConfig{}, config.EncryptionConfig{Enabled: true, KeyringFile: path, Prefixes: []string{"secrets/"}}, "users/1", small, "", ""},
+		{"under an encryption prefix", config.CompressionConfig{}, config.EncryptionConfig{Enabled: true, KeyringFile: path, Prefixes: []string{"secrets/"}}, "secrets/1", small, CodecMsgpack, "2025-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testRepository(t, tt.compression, tt.encryption)
+
+			rec, err := repo.toRecord(domain.Payload{Key: tt.key, Value: tt.value})
+			if err != nil {
+				t.Fatalf("toRecord: %v", err)
+			}
+			if rec.Codec != tt.codec || rec.KeyID != tt.kid {
+				t.Fatalf("record codec %q, kid %q, want %q, %q", rec.Codec, rec.KeyID, tt.codec, tt.kid)
+			}
+
+			payload, err := repo.toPayload(storedRecord(t, rec))
+			if err != nil {
+				t.Fatalf("toPayload: %v", err)
+			}
+			if payload.Key != tt.key || !reflect.DeepEqual(payload.Value, tt.value) {
+				t.Fatalf("payload %v, want %s: %v", payload, tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestRecordCoexistence(t *testing.T) {
+	// Records written plain, compressed, encrypted, and both, are read back
+	// by a repository with compression and encryption enabled.
+	path := writeKeyring(t, "2025-01", "2024-06", "2025-01")
+	large := map[string]any{"text": strings.Repeat("compressible ", 100)}
+	compression := config.CompressionConfig{Enabled: true, Algorithm: CodecZstd, Threshold: 64}
+	encryption := config.EncryptionConfig{Enabled: true, KeyringFile: path}
+
+	var records []record
+	for _, repo := range []Tarantool{
+		testRepository(t, config.CompressionConfig{}, config.EncryptionConfig{}),
+		testRepository(t, compression, config.EncryptionConfig{}),
+		testRepository(t, config.CompressionConfig{}, encryption),
+		testRepository(t, compression, encryption),
+	} {
+		rec, err := repo.toRecord(domain.Payload{Key: "k", Value: large})
+		if err != nil {
+			t.Fatalf("toRecord: %v", err)
+		}
+		records = append(records, storedRecord(t, rec))
+	}
+
+	current := testRepository(t, compression, encryption)
+	for _, rec := range records {
+		payload, err := current.toPayload(rec)
+		if err != nil {
+			t.Fatalf("toPayload of codec %q, kid %q: %v", rec.Codec, rec.KeyID, err)
+		}
+		if !reflect.DeepEqual(payload.Value, large) {
+			t.Fatalf("payload of codec %q, kid %q is %v, want %v", rec.Codec, rec.KeyID, payload.Value, large)
+		}
+	}
+}
+
+func TestRecordOpenErrors(t *testing.T) {
+	path := writeKeyring(t, "2025-01", "2025-01")
+	repo := testRepository(t, config.CompressionConfig{}, config.EncryptionConfig{Enabled: true, KeyringFile: path})
+
+	rec, err := repo.toRecord(domain.Payload{Key: "users/1", Value: map[string]any{"name": "Ann"}})
+	if err != nil {
+		t.Fatalf("toRecord: %v", err)
+	}
+
+	moved := rec
+	moved.Key = "users/2"
+	unknown := rec
+	unknown.KeyID = "2023-01"
+	notBinary := rec
+	notBinary.Value = map[string]any{}
+
+	tests := []struct {
+		name string
+		rec  record
+		err  error // nil for any error
+	}{
+		{"sealed for another key", moved, nil},
+		{"unknown kid", unknown, ErrUnknownKeyID},
+		{"encrypted value is not binary", notBinary, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.toPayload(tt.rec)
+			if err == nil {
+				t.Fatal("toPayload succeeded, want an error")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("toPayload error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRecordDecodeOlderTuples(t *testing.T) {
+	tests := []struct {
+		name  string
+		tuple []any
+		want  record
+	}{
+		{"key and value", []any{"k", map[string]any{"a": "b"}}, record{Key: "k", Value: map[string]any{"a": "b"}}},
+		{"with codec", []any{"k", []byte{1}, CodecZstd}, record{Key: "k", Value: []byte{1}, Codec: CodecZstd}},
+		{"null kid", []any{"k", []byte{1}, CodecZstd, nil}, record{Key: "k", Value: []byte{1}, Codec: CodecZstd}},
+		{"extra fields", []any{"k", []byte{1}, CodecMsgpack, "2025-01", nil, "later"}, record{Key: "k", Value: []byte{1}, Codec: CodecMsgpack, KeyID: "2025-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := msgpack.Marshal(tt.tuple)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rec record
+			if err := msgpack.Unmarshal(data, &rec); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(rec, tt.want) {
+				t.Fatalf("record %+v, want %+v", rec, tt.want)
+			}
+		})
+	}
+}
+
+// testRepository returns a repository without a connection, for encoding
+// and decoding records only.
+func testRepository(t *testing.T, compression config.CompressionConfig, encryption config.EncryptionConfig) Tarantool {
+	t.Helper()
+	if compression.Algorithm == "" {
+		compression.Algorithm = CodecZstd
+	}
+
+	codec, err := newValueCodec(compression)
+	if err != nil {
+		t.Fatalf("newValueCodec: %v", err)
+	}
+	cipher, err := newValueCipher(encryption)
+	if err != nil {
+		t.Fatalf("newValueCipher: %v", err)
+	}
+	return Tarantool{codec: codec, cipher: cipher}
+}
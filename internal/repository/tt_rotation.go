@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.KeyRotationRepository = Tarantool{} // Tarantool must satisfy KeyRotationRepository
+
+// ReloadKeyring re-reads the keyring file and reports whether it changed.
+// A change restarts the passes of RotateKeys, which re-seal values written
+// in plain or with another key since the last pass completed.
+func (tt Tarantool) ReloadKeyring(ctx context.Context) (bool, error) {
+	if !tt.cipher.enabled {
+		return false, nil
+	}
+
+	changed, err := tt.cipher.reload()
+	if err != nil || !changed {
+		return changed, err
+	}
+	return true, tt.resetRotationCursors(ctx)
+}
+
+// rotationCursor is a tuple of `kv_rotation`: the progress of the pass
+// re-encrypting a space with the active key KeyID.
+type rotationCursor struct {
+	_msgpack struct{} `msgpack:",as_array"` //nolint:unused
+	Space    string
+	KeyID    string
+	After    []any // primary key of the last tuple visited
+	Done     bool
+}
+
+// sealedRow is a value of a space holding sealed values along with the
+// primary key of its tuple.
+type sealedRow struct {
+	primaryKey []any
+	rec        record
+}
+
+// sealedSpace is a space holding sealed values. scan returns up to limit
+// of its rows after the primary key after, the first ones when empty.
+type sealedSpace struct {
+	name string
+	scan func(ctx context.Context, after []any, limit int) ([]sealedRow, error)
+}
+
+// sealedSpaces lists the spaces re-encrypted by RotateKeys.
+func (tt Tarantool) sealedSpaces() []sealedSpace {
+	return []sealedSpace{
+		{name: defaultSpace, scan: tt.scanSealedRecords},
+		{name: "kv_history", scan: tt.scanSealedVersions},
+		{name: "kv_trash", scan: tt.scanSealedTrash},
+	}
+}
+
+// RotateKeys re-encrypts every value sealed with a non-active key, current
+// values, their versions and the trash alike, and seals the plain values of
+// keys that are to be encrypted. Values modified concurrently are skipped,
+// they are already sealed with the active key by the writer. Values that
+// cannot be re-encrypted are logged and skipped. The pass over a space
+// resumes after the last batch of the previous one and does nothing once
+// complete, until the active key changes or the keyring is reloaded.
+func (tt Tarantool) RotateKeys(ctx context.Context, batchSize int) (int, error) {
+	if !tt.cipher.enabled {
+		return 0, nil
+	}
+
+	rotated := 0
+	for _, space := range tt.sealedSpaces() {
+		n, err := tt.rotateSpace(ctx, space, batchSize)
+		rotated += n
+		if err != nil {
+			return rotated, err
+		}
+	}
+	return rotated, nil
+}
+
+func (tt Tarantool) rotateSpace(ctx context.Context, space sealedSpace, batchSize int) (int, error) {
+	active := tt.cipher.activeKeyID()
+	cursor, err := tt.selectRotationCursor(ctx, space.name)
+	if err != nil {
+		return 0, err
+	}
+	if cursor.KeyID != active {
+		cursor = rotationCursor{Space: space.name, KeyID: active}
+	}
+
+	rotated := 0
+	for !cursor.Done {
+		batch, err := space.scan(ctx, cursor.After, batchSize)
+		if err != nil {
+			return rotated, err
+		}
+
+		for _, row := range batch {
+			if !tt.stale(row.rec, active) {
+				continue
+			}
+
+			swapped, err := tt.reseal(ctx, space.name, row)
+			if err != nil {
+				utils.LoggerFromContext(ctx, tt.log).Warn("Failed to re-encrypt value, skipped",
+					"space", space.name,
+					"key", row.rec.Key,
+					"kid", row.rec.KeyID,
+					"error", err,
+				)
+				continue
+			}
+			if swapped {
+				rotated++
+			}
+		}
+
+		if len(batch) > 0 {
+			cursor.After = batch[len(batch)-1].primaryKey
+		}
+		cursor.Done = len(batch) < batchSize
+		if err := tt.replaceRotationCursor(ctx, cursor); err != nil {
+			return rotated, err
+		}
+	}
+
+	return rotated, nil
+}
+
+// stale reports whether the record is to be sealed again with the active
+// key: it is sealed with another key, or stored in plain while its key is
+// to be encrypted, e.g. written before encryption was enabled.
+func (tt Tarantool) stale(rec record, active string) bool {
+	if rec.KeyID == "" {
+		return tt.cipher.appliesTo(rec.Key)
+	}
+	return rec.KeyID != active
+}
+
+func (tt Tarantool) selectRotationCursor(ctx context.Context, space string) (rotationCursor, error) {
+	request := tarantool.NewSelectRequest("kv_rotation").
+		Key(tarantool.StringKey{S: space}).
+		Context(ctx)
+
+	var result []rotationCursor
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return rotationCursor{}, ErrSelectOperationFail
+	}
+	if len(result) == 0 {
+		return rotationCursor{Space: space}, nil
+	}
+	return result[0], nil
+}
+
+func (tt Tarantool) replaceRotationCursor(ctx context.Context, cursor rotationCursor) error {
+	if cursor.After == nil {
+		cursor.After = []any{}
+	}
+	request := tarantool.NewReplaceRequest("kv_rotation").Tuple(&cursor).Context(ctx)
+
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		return ErrInsertOperationFail
+	}
+	return nil
+}
+
+// resetRotationCursors removes the progress of every pass, so the next
+// RotateKeys starts over.
+func (tt Tarantool) resetRotationCursors(ctx context.Context) error {
+	for _, space := range tt.sealedSpaces() {
+		request := tarantool.NewDeleteRequest("kv_rotation").
+			Key(tarantool.StringKey{S: space.name}).
+			Context(ctx)
+
+		if _, err := tt.conn.Do(request).Get(); err != nil {
+			return ErrDeleteOperationFail
+		}
+	}
+	return nil
+}
+
+// scanRecords returns up to limit records with keys strictly after the given one.
+func (tt Tarantool) scanRecords(ctx context.Context, after string, limit int) ([]record, error) {
+	return tt.scanTiers(ctx, tarantool.IterGt, after, limit)
+}
+
+func (tt Tarantool) scanSealedRecords(ctx context.Context, after []any, limit int) ([]sealedRow, error) {
+	key := ""
+	if len(after) > 0 {
+		key, _ = after[0].(string)
+	}
+
+	records, err := tt.scanRecords(ctx, key, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sealedRow, len(records))
+	for i, rec := range records {
+		rows[i] = sealedRow{primaryKey: []any{rec.Key}, rec: rec}
+	}
+	return rows, nil
+}
+
+func (tt Tarantool) scanSealedVersions(ctx context.Context, after []any, limit int) ([]sealedRow, error) {
+	if after == nil {
+		after = []any{}
+	}
+	request := tarantool.NewSelectRequest("kv_history").
+		Iterator(tarantool.IterGt).
+		Key(after).
+		Limit(uint32(limit)).
+		Context(ctx)
+
+	var result []versionRecord
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	rows := make([]sealedRow, len(result))
+	for i, vr := range result {
+		rows[i] = sealedRow{
+			primaryKey: []any{vr.Key, vr.Version},
+			rec:        record{Key: vr.Key, Value: vr.Value, Codec: vr.Codec, KeyID: vr.KeyID},
+		}
+	}
+	return rows, nil
+}
+
+func (tt Tarantool) scanSealedTrash(ctx context.Context, after []any, limit int) ([]sealedRow, error) {
+	if after == nil {
+		after = []any{}
+	}
+	request := tarantool.NewSelectRequest("kv_trash").
+		Iterator(tarantool.IterGt).
+		Key(after).
+		Limit(uint32(limit)).
+		Context(ctx)
+
+	var result []record
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	rows := make([]sealedRow, len(result))
+	for i, rec := range result {
+		rows[i] = sealedRow{primaryKey: []any{rec.Key}, rec: rec}
+	}
+	return rows, nil
+}
+
+// reseal re-encrypts the value of the row with the active key unless it
+// changed since it was read, and reports whether it did.
+func (tt Tarantool) reseal(ctx context.Context, space string, row sealedRow) (bool, error) {
+	rec, err := tt.open(row.rec)
+	if err != nil {
+		return false, err
+	}
+	if rec, err = tt.seal(rec); err != nil {
+		return false, err
+	}
+
+	request := tarantool.NewCallRequest("kv_reseal_value").
+		Args([]any{space, row.primaryKey, optionalString(row.rec.KeyID), row.rec.Value, rec.Value, rec.Codec, rec.KeyID}).
+		Context(ctx)
+
+	var result []bool
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return false, ErrUpdateOperationFail
+	}
+
+	return len(result) > 0 && result[0], nil
+}
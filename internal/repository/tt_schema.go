@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.SchemaRepository = Tarantool{} // Tarantool must satisfy SchemaRepository
+
+func (tt Tarantool) SelectSchemas(ctx context.Context) ([]domain.Schema, error) {
+	request := tarantool.NewSelectRequest("kv_schemas").
+		Iterator(tarantool.IterAll).
+		Context(ctx)
+
+	var result []domain.Schema
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	return result, nil
+}
+
+func (tt Tarantool) ReplaceSchema(ctx context.Context, schema domain.Schema) error {
+	request := tarantool.NewReplaceRequest("kv_schemas").Tuple(&schema).Context(ctx)
+
+	if _, err := tt.conn.Do(request).Get(); err != nil {
+		return ErrInsertOperationFail
+	}
+
+	return nil
+}
+
+func (tt Tarantool) DeleteSchema(ctx context.Context, prefix string) (domain.Schema, error) {
+	request := tarantool.NewDeleteRequest("kv_schemas").
+		Key(tarantool.StringKey{S: prefix}).
+		Context(ctx)
+
+	var result []domain.Schema
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return domain.Schema{}, ErrDeleteOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.Schema{}, ErrNotFound
+	}
+
+	return result[0], nil
+}
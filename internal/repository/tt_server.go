@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.ServerRepository = Tarantool{} // Tarantool must satisfy ServerRepository
+
+// Ping checks the connection and returns the version the server announced
+// in its greeting.
+func (tt Tarantool) Ping(ctx context.Context) (string, error) {
+	if _, err := tt.conn.Do(tarantool.NewPingRequest().Context(ctx)).Get(); err != nil {
+		return "", err
+	}
+	return tt.conn.Greeting.Version, nil
+}
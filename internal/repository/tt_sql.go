@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.SQLRepository = Tarantool{} // Tarantool must satisfy SQLRepository
+
+// Execute runs the statement wrapped into a subquery limited to one row
+// above q.Limit, so truncation can be reported without fetching every row.
+// The statement must have no comments and no trailing semicolon. Tarantool
+// stops it after q.MaxSteps instructions, a setting of the session of the
+// connection that every statement sets before running.
+func (tt Tarantool) Execute(ctx context.Context, q domain.SQLQuery) (domain.SQLResult, error) {
+	settings := tarantool.NewUpdateRequest("_session_settings").
+		Key(tarantool.StringKey{S: "sql_vdbe_max_steps"}).
+		Operations(tarantool.NewOperations().Assign(1, q.MaxSteps)).
+		Context(ctx)
+	if _, err := tt.conn.Do(settings).Get(); err != nil {
+		return domain.SQLResult{}, ErrUpdateOperationFail
+	}
+
+	statement := fmt.Sprintf("SELECT * FROM (%s) LIMIT %d", q.Statement, q.Limit+1)
+
+	params := q.Params
+	if params == nil {
+		params = []any{}
+	}
+
+	request := tarantool.NewExecuteRequest(statement).Args(params).Context(ctx)
+
+	response, err := tt.conn.Do(request).GetResponse()
+	if err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			return domain.SQLResult{}, fmt.Errorf("%w: %s", ErrSQLStatementFail, tntErr.Msg)
+		}
+		return domain.SQLResult{}, err
+	}
+
+	executeResponse, ok := response.(*tarantool.ExecuteResponse)
+	if !ok {
+		return domain.SQLResult{}, ErrSelectOperationFail
+	}
+
+	rows, err := executeResponse.Decode()
+	if err != nil {
+		return domain.SQLResult{}, ErrSelectOperationFail
+	}
+
+	metadata, err := executeResponse.MetaData()
+	if err != nil {
+		return domain.SQLResult{}, ErrSelectOperationFail
+	}
+
+	result := domain.SQLResult{Columns: make([]domain.SQLColumn, 0, len(metadata))}
+	for _, column := range metadata {
+		result.Columns = append(result.Columns, domain.SQLColumn{
+			Name:     column.FieldName,
+			Type:     column.FieldType,
+			Nullable: column.FieldIsNullable,
+		})
+	}
+
+	if len(rows) > q.Limit {
+		rows, result.Truncated = rows[:q.Limit], true
+	}
+
+	result.Rows = make([][]any, 0, len(rows))
+	for _, row := range rows {
+		values, ok := row.([]any)
+		if !ok {
+			return domain.SQLResult{}, ErrSelectOperationFail
+		}
+		result.Rows = append(result.Rows, values)
+	}
+
+	return result, nil
+}
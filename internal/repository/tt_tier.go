@@ -0,0 +1,86 @@
+// Tiering of keys between `kv_storage` in memory and `kv_cold` on disk.
+
+package repository
+
+import (
+	"context"
+	"tarantool-app/internal/interfaces"
+	"time"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.TieringRepository = Tarantool{} // Tarantool must satisfy TieringRepository
+
+// getRequest reads the key from either tier, promoting a cold key back to
+// memory. The access is tracked for demotion while tiering is enabled.
+func (tt Tarantool) getRequest(ctx context.Context, key string) *tarantool.CallRequest {
+	return tarantool.NewCallRequest("kv_tier_get").
+		Args([]any{key, tt.tiering.Enabled}).
+		Context(ctx)
+}
+
+// demoteResult is the result of `kv_tier_demote`.
+type demoteResult struct {
+	_msgpack struct{} `msgpack:",as_array"` //nolint:unused
+	Demoted  int
+	Next     string
+}
+
+// DemoteIdle sweeps up to limit keys in memory after the key after, empty
+// for the first, and demotes those not accessed for idle to disk. It
+// returns the number of keys demoted and the key to continue after, empty
+// once the sweep is complete.
+func (tt Tarantool) DemoteIdle(ctx context.Context, idle time.Duration, after string, limit int) (int, string, error) {
+	request := tarantool.NewCallRequest("kv_tier_demote").
+		Args([]any{idle.Milliseconds(), after, limit}).
+		Context(ctx)
+
+	var result demoteResult
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return 0, "", ErrUpdateOperationFail
+	}
+	return result.Demoted, result.Next, nil
+}
+
+// scanTiers returns up to limit records of both tiers in key order from
+// the key on, as selected by iterator. A key in both tiers for a moment
+// is returned once, from memory.
+func (tt Tarantool) scanTiers(ctx context.Context, iterator tarantool.Iter, key string, limit int) ([]record, error) {
+	futures := make([]*tarantool.Future, 2)
+	for i, space := range []string{defaultSpace, coldSpace} {
+		request := tarantool.NewSelectRequest(space).
+			Iterator(iterator).
+			Key(tarantool.StringKey{S: key}).
+			Limit(uint32(limit)).
+			Context(ctx)
+		futures[i] = tt.conn.Do(request)
+	}
+
+	var hot, cold []record
+	if err := futures[0].GetTyped(&hot); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+	if err := futures[1].GetTyped(&cold); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	return mergeTiers(hot, cold, limit), nil
+}
+
+// mergeTiers merges records of both tiers sorted by key, keeping the hot
+// record of a key in both.
+func mergeTiers(hot, cold []record, limit int) []record {
+	merged := make([]record, 0, min(len(hot)+len(cold), limit))
+	for len(merged) < limit && (len(hot) > 0 || len(cold) > 0) {
+		switch {
+		case len(cold) == 0 || len(hot) > 0 && hot[0].Key < cold[0].Key:
+			merged, hot = append(merged, hot[0]), hot[1:]
+		case len(hot) == 0 || cold[0].Key < hot[0].Key:
+			merged, cold = append(merged, cold[0]), cold[1:]
+		default:
+			merged, hot, cold = append(merged, hot[0]), hot[1:], cold[1:]
+		}
+	}
+	return merged
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMergeTiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		hot   []string
+		cold  []string
+		limit int
+		want  []string
+	}{
+		{"both empty", nil, nil, 10, []string{}},
+		{"hot only", []string{"a", "b"}, nil, 10, []string{"a", "b"}},
+		{"cold only", nil, []string{"a", "b"}, 10, []string{"a", "b"}},
+		{"interleaved", []string{"a", "c", "e"}, []string{"b", "d"}, 10, []string{"a", "b", "c", "d", "e"}},
+		{"hot after cold", []string{"x", "y"}, []string{"a", "b"}, 10, []string{"a", "b", "x", "y"}},
+		{"key in both tiers once", []string{"a", "b"}, []string{"b", "c"}, 10, []string{"a", "b", "c"}},
+		{"limit", []string{"a", "c"}, []string{"b", "d"}, 3, []string{"a", "b", "c"}},
+		{"limit on a key in both tiers", []string{"a", "b"}, []string{"b"}, 2, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeTiers(tierRecords(tt.hot, "hot"), tierRecords(tt.cold, "cold"), tt.limit)
+
+			keys := make([]string, len(merged))
+			for i, rec := range merged {
+				keys[i] = rec.Key
+				if rec.Value == "cold" && slices.Contains(tt.hot, rec.Key) {
+					t.Errorf("key %q is merged from the cold tier, want the hot record", rec.Key)
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Fatalf("merged keys %q, want %q", keys, tt.want)
+			}
+		})
+	}
+}
+
+// tierRecords returns records of the keys whose value names the tier.
+func tierRecords(keys []string, tier string) []record {
+	records := make([]record, len(keys))
+	for i, key := range keys {
+		records[i] = record{Key: key, Value: tier}
+	}
+	return records
+}
@@ -0,0 +1,111 @@
+// Bulk export and import of `kv_storage`.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.TransferRepository = Tarantool{} // Tarantool must satisfy TransferRepository
+
+// ScanPayloads returns up to limit decoded values with the key prefix,
+// starting after the given key, or from the prefix when after is empty.
+// A short page means there are no more keys with the prefix.
+func (tt Tarantool) ScanPayloads(ctx context.Context, prefix, after string, limit int) ([]domain.Payload, error) {
+	iterator, key := tarantool.IterGe, prefix
+	if after != "" {
+		iterator, key = tarantool.IterGt, after
+	}
+
+	result, err := tt.scanTiers(ctx, iterator, key, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([]domain.Payload, 0, len(result))
+	for _, rec := range result {
+		if !strings.HasPrefix(rec.Key, prefix) {
+			break
+		}
+		payload, err := tt.toPayload(rec)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
+
+// ExistingKeys reports which of the keys are stored in either tier.
+// Lookups are pipelined.
+func (tt Tarantool) ExistingKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	spaces := []string{defaultSpace, coldSpace}
+	futures := make([]*tarantool.Future, 0, len(keys)*len(spaces))
+	for _, key := range keys {
+		for _, space := range spaces {
+			request := tarantool.NewSelectRequest(space).
+				Key(tarantool.StringKey{S: key}).
+				Limit(1).
+				Context(ctx)
+			futures = append(futures, tt.conn.Do(request))
+		}
+	}
+
+	existing := make(map[string]bool, len(keys))
+	for i, future := range futures {
+		var result []record
+		if err := future.GetTyped(&result); err != nil {
+			return nil, ErrSelectOperationFail
+		}
+		if len(result) > 0 {
+			existing[keys[i/len(spaces)]] = true
+		}
+	}
+
+	return existing, nil
+}
+
+// WriteBatch inserts, or replaces when overwrite is set, the payloads with
+// pipelined requests and returns the error of each of them.
+func (tt Tarantool) WriteBatch(ctx context.Context, payloads []domain.Payload, overwrite bool) []error {
+	errs := make([]error, len(payloads))
+	futures := make([]*tarantool.Future, len(payloads))
+
+	for i, payload := range payloads {
+		rec, err := tt.toRecord(payload)
+		if err != nil {
+			errs[i] = ErrInsertOperationFail
+			continue
+		}
+
+		op := "insert"
+		if overwrite {
+			op = "replace"
+		}
+		futures[i] = tt.conn.Do(tt.auditedWrite(ctx, rec.Key, payload.Value, op, &rec))
+	}
+
+	for i, future := range futures {
+		if future == nil {
+			continue
+		}
+		if _, err := future.Get(); err != nil {
+			var tntErr tarantool.Error
+			if errors.As(err, &tntErr) && tntErr.Code == iproto.ER_TUPLE_FOUND {
+				errs[i] = ErrAlreadyExists
+			} else {
+				errs[i] = ErrInsertOperationFail
+			}
+		}
+	}
+
+	return errs
+}
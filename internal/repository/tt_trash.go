@@ -0,0 +1,41 @@
+// Soft-deleted keys kept in `kv_trash`.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"tarantool-app/internal/domain"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Error code raised by `kv_audited` undeleting a key.
+const errCodeNotInTrash iproto.Error = 10013
+
+// Undelete moves a key deleted in soft-delete mode back from the trash,
+// unless its retention expired or the key was created again since.
+func (tt Tarantool) Undelete(ctx context.Context, rq domain.Payload) (domain.Payload, error) {
+	request := tt.audited(ctx, rq.Key, "undelete", rq.Key)
+
+	var result []record
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		var tntErr tarantool.Error
+		if errors.As(err, &tntErr) {
+			switch tntErr.Code {
+			case errCodeNotInTrash:
+				return domain.Payload{}, ErrNotInTrash
+			case iproto.ER_TUPLE_FOUND:
+				return domain.Payload{}, ErrAlreadyExists
+			}
+		}
+		return domain.Payload{}, ErrInsertOperationFail
+	}
+
+	if len(result) == 0 {
+		return domain.Payload{}, ErrInsertOperationFail
+	}
+
+	return tt.toPayload(result[0])
+}
@@ -0,0 +1,89 @@
+// Hierarchical operations on path-like keys.
+
+package repository
+
+import (
+	"context"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+var _ interfaces.TreeRepository = Tarantool{} // Tarantool must satisfy TreeRepository
+
+// SelectChildren returns up to limit immediate children of the keys with
+// the prefix, in key order after the child named after.
+func (tt Tarantool) SelectChildren(ctx context.Context, prefix, after string, limit int) ([]domain.Child, error) {
+	request := tarantool.NewCallRequest("kv_children").
+		Args([]any{prefix, after, limit}).
+		Context(ctx)
+
+	var result [][]domain.Child
+	if err := tt.conn.Do(request).GetTyped(&result); err != nil {
+		return nil, ErrSelectOperationFail
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+// ScanKeys returns up to limit keys with the prefix after the given key,
+// or from the prefix when after is empty.
+func (tt Tarantool) ScanKeys(ctx context.Context, prefix, after string, limit int) ([]string, error) {
+	iterator, key := tarantool.IterGe, prefix
+	if after != "" {
+		iterator, key = tarantool.IterGt, after
+	}
+
+	result, err := tt.scanTiers(ctx, iterator, key, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(result))
+	for _, rec := range result {
+		if !strings.HasPrefix(rec.Key, prefix) {
+			break
+		}
+		keys = append(keys, rec.Key)
+	}
+	return keys, nil
+}
+
+// DeleteKeys deletes the keys with pipelined requests, moving them to the
+// trash in soft-delete mode, and returns the number of keys deleted.
+// Missing keys are skipped.
+func (tt Tarantool) DeleteKeys(ctx context.Context, keys []string) (int, error) {
+	futures := make([]*tarantool.Future, len(keys))
+	for i, key := range keys {
+		futures[i] = tt.conn.Do(tt.deleteRequest(ctx, key))
+	}
+
+	deleted, failed := 0, false
+	for _, future := range futures {
+		var result []record
+		if err := future.GetTyped(&result); err != nil {
+			failed = true
+		} else if len(result) > 0 {
+			deleted++
+		}
+	}
+
+	if failed {
+		return deleted, ErrDeleteOperationFail
+	}
+	return deleted, nil
+}
+
+// deleteRequest deletes the key, or moves it to the trash in soft-delete
+// mode.
+func (tt Tarantool) deleteRequest(ctx context.Context, key string) *tarantool.CallRequest {
+	if tt.trash.Enabled {
+		return tt.audited(ctx, key, "soft_delete", key, tt.trash.Retention.Milliseconds())
+	}
+	return tt.audited(ctx, key, "delete", []any{key})
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+var ErrInvalidAuditQuery = errors.New("400 invalid audit query")
+
+// AuditUseCase reads the audit trail written by Tarantool along with every
+// change of a stored value.
+type AuditUseCase struct {
+	repo interfaces.AuditRepository
+	log  interfaces.Logger
+}
+
+var _ interfaces.AuditUseCase = AuditUseCase{} // AuditUseCase must satisfy interfaces.AuditUseCase
+
+func NewAuditUseCase(repo interfaces.AuditRepository, log interfaces.Logger) AuditUseCase {
+	return AuditUseCase{repo: repo, log: log}
+}
+
+// Query returns one page of entries, oldest first. The next page starts
+// after the ID of the last entry.
+func (uc AuditUseCase) Query(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultAuditLimit
+	case q.Limit < 0 || q.Limit > MaxAuditLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditQuery, MaxAuditLimit)
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidAuditQuery)
+	}
+
+	return uc.repo.SelectAudit(ctx, q)
+}
+
+// Export writes every entry matching the query to w as NDJSON, oldest
+// first, and returns the number of entries written. The limit of the
+// query is ignored.
+func (uc AuditUseCase) Export(ctx context.Context, w io.Writer, q domain.AuditQuery) (int, error) {
+	enc := json.NewEncoder(w)
+	q.Limit = MaxAuditLimit
+
+	exported := 0
+	for {
+		entries, err := uc.Query(ctx, q)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+
+		if len(entries) < q.Limit {
+			return exported, nil
+		}
+		q.After = entries[len(entries)-1].ID
+	}
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrBlobTooLarge = errors.New("413 value is too large")
+
+type BlobUseCase struct {
+	repo          interfaces.BlobRepository
+	log           interfaces.Logger
+	chunkSize     int
+	maxSize       int64
+	uploadTimeout time.Duration
+	retention     time.Duration
+}
+
+var _ interfaces.BlobUseCase = BlobUseCase{} // BlobUseCase must satisfy interfaces.BlobUseCase
+
+func NewBlobUseCase(repo interfaces.BlobRepository, cfg config.BlobConfig, log interfaces.Logger) BlobUseCase {
+	return BlobUseCase{
+		repo:          repo,
+		log:           log,
+		chunkSize:     cfg.ChunkSize,
+		maxSize:       cfg.MaxSize,
+		uploadTimeout: cfg.UploadTimeout,
+		retention:     cfg.Retention,
+	}
+}
+
+// Upload streams body into chunks of a fresh generation and publishes it
+// only once every chunk is stored, so readers never observe a partial value.
+// The replaced generation stays readable for the retention, so downloads
+// already streaming it are not cut short.
+func (uc BlobUseCase) Upload(ctx context.Context, key, contentType string, body io.Reader) (domain.BlobMeta, error) {
+	if err := domain.ValidateKey(key); err != nil {
+		return domain.BlobMeta{}, err
+	}
+
+	meta := domain.BlobMeta{
+		Key:         key,
+		Generation:  uuid.NewString(),
+		ChunkSize:   uint64(uc.chunkSize),
+		ContentType: contentType,
+	}
+
+	if err := uc.repo.BeginBlob(ctx, key, meta.Generation, uc.uploadTimeout); err != nil {
+		return domain.BlobMeta{}, err
+	}
+
+	err := uc.writeChunks(ctx, &meta, body)
+	if err == nil {
+		var committed domain.BlobMeta
+		if committed, err = uc.repo.CommitBlob(ctx, meta, uc.retention); err == nil {
+			return committed, nil
+		}
+	}
+
+	// Chunks left behind, e.g. by a crash, are collected once the upload
+	// times out.
+	if abortErr := uc.repo.AbortBlob(context.WithoutCancel(ctx), key, meta.Generation); abortErr != nil {
+		utils.LoggerFromContext(ctx, uc.log).Warn("Failed to clean up aborted blob upload",
+			"key", key,
+			"generation", meta.Generation,
+			"error", abortErr,
+		)
+	}
+	return domain.BlobMeta{}, err
+}
+
+func (uc BlobUseCase) writeChunks(ctx context.Context, meta *domain.BlobMeta, body io.Reader) error {
+	buf := make([]byte, uc.chunkSize)
+
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 {
+			if int64(meta.Size)+int64(n) > uc.maxSize {
+				return ErrBlobTooLarge
+			}
+
+			chunk := domain.BlobChunk{
+				Key:        meta.Key,
+				Generation: meta.Generation,
+				Seq:        meta.Chunks,
+				Data:       buf[:n],
+			}
+			if err := uc.repo.InsertChunk(ctx, chunk); err != nil {
+				return err
+			}
+
+			meta.Size += uint64(n)
+			meta.Chunks++
+		}
+
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// Open returns the blob metadata and a lazily loading reader over its chunks.
+func (uc BlobUseCase) Open(ctx context.Context, key string) (domain.BlobMeta, io.ReadSeeker, error) {
+	meta, err := uc.repo.SelectBlob(ctx, key)
+	if err != nil {
+		return domain.BlobMeta{}, nil, err
+	}
+
+	return meta, newBlobReader(ctx, uc.repo, meta), nil
+}
+
+// Delete deletes the blob. Its chunks stay readable for the retention.
+func (uc BlobUseCase) Delete(ctx context.Context, key string) (domain.BlobMeta, error) {
+	return uc.repo.DeleteBlob(ctx, key, uc.retention)
+}
+
+// blobReader implements io.ReadSeeker over the chunks of a single generation,
+// fetching at most one chunk at a time.
+type blobReader struct {
+	ctx    context.Context
+	repo   interfaces.BlobRepository
+	meta   domain.BlobMeta
+	offset int64
+	seq    int64
+	chunk  []byte
+}
+
+func newBlobReader(ctx context.Context, repo interfaces.BlobRepository, meta domain.BlobMeta) *blobReader {
+	return &blobReader{ctx: ctx, repo: repo, meta: meta, seq: -1}
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	size := int64(r.meta.Size)
+	if r.offset >= size {
+		return 0, io.EOF
+	}
+
+	chunkSize := int64(r.meta.ChunkSize)
+	seq := r.offset / chunkSize
+	if seq != r.seq {
+		chunk, err := r.repo.SelectChunk(r.ctx, r.meta.Key, r.meta.Generation, uint64(seq))
+		if err != nil {
+			return 0, err
+		}
+		r.seq, r.chunk = seq, chunk.Data
+	}
+
+	start := r.offset - seq*chunkSize
+	if start >= int64(len(r.chunk)) {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	n := copy(p, r.chunk[start:])
+	r.offset += int64(n)
+	return n, nil
+}
+
+func (r *blobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += int64(r.meta.Size)
+	default:
+		return 0, errors.New("blob reader: invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("blob reader: negative position")
+	}
+
+	r.offset = offset
+	return offset, nil
+}
@@ -0,0 +1,249 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+const (
+	DefaultScanLimit = 100
+	MaxScanLimit     = 1000
+)
+
+var (
+	ErrInvalidScan        = errors.New("400 invalid scan")
+	ErrCollectionDeclared = errors.New("409 collection is declared in the configuration")
+	ErrCollectionDefault  = errors.New("409 collection is the default one")
+)
+
+// defaultCollection describes domain.DefaultCollection, whose keys are
+// served under /kv rather than by CollectionUseCase.
+var defaultCollection = domain.Collection{
+	Name:    domain.DefaultCollection,
+	Engine:  domain.EngineMemtx,
+	Key:     []domain.KeyPart{{Field: "key", Type: domain.KeyPartString}},
+	Indexes: []domain.CollectionIndex{},
+}
+
+// CollectionUseCase serves the items of the collections declared in the
+// configuration and of those defined over HTTP, registered in Tarantool.
+// Keys are given as the text form of their parts, parsed by the key schema
+// of the collection. Values are stored as they are: collections have no
+// schemas, compression, encryption, audit, history or trash.
+type CollectionUseCase struct {
+	repo     interfaces.CollectionRepository
+	declared map[string]domain.Collection // by name
+	log      interfaces.Logger
+}
+
+var _ interfaces.CollectionUseCase = CollectionUseCase{} // CollectionUseCase must satisfy interfaces.CollectionUseCase
+
+func NewCollectionUseCase(repo interfaces.CollectionRepository, cfg []config.CollectionConfig, log interfaces.Logger) CollectionUseCase {
+	declared := make(map[string]domain.Collection, len(cfg))
+	for _, cc := range cfg {
+		collection := domain.Collection{
+			Name:    cc.Name,
+			Engine:  domain.EngineMemtx,
+			Key:     make([]domain.KeyPart, len(cc.Key)),
+			Indexes: []domain.CollectionIndex{},
+		}
+		for i, part := range cc.Key {
+			collection.Key[i] = domain.KeyPart{Field: part.Field, Type: part.Type}
+		}
+		declared[cc.Name] = collection
+	}
+	return CollectionUseCase{repo: repo, declared: declared, log: log}
+}
+
+// Create defines a new collection and creates its space.
+func (uc CollectionUseCase) Create(ctx context.Context, c domain.Collection) (domain.Collection, error) {
+	if c.Engine == "" {
+		c.Engine = domain.EngineMemtx
+	}
+	if c.Indexes == nil {
+		c.Indexes = []domain.CollectionIndex{}
+	}
+	if err := c.Validate(); err != nil {
+		return domain.Collection{}, err
+	}
+	return c, uc.repo.CreateCollection(ctx, c)
+}
+
+// Drop drops a collection defined over HTTP together with its items.
+func (uc CollectionUseCase) Drop(ctx context.Context, name string) error {
+	if name == domain.DefaultCollection {
+		return fmt.Errorf("%w: %s", ErrCollectionDefault, name)
+	}
+	if _, ok := uc.declared[name]; ok {
+		return fmt.Errorf("%w: %s", ErrCollectionDeclared, name)
+	}
+	return uc.repo.DropCollection(ctx, name)
+}
+
+// List returns every collection by name, the declared ones and the default
+// one included.
+func (uc CollectionUseCase) List(ctx context.Context) ([]domain.Collection, error) {
+	defined, err := uc.repo.SelectCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := slices.AppendSeq(append(defined, defaultCollection), maps.Values(uc.declared))
+	slices.SortFunc(collections, func(a, b domain.Collection) int { return strings.Compare(a.Name, b.Name) })
+	return collections, nil
+}
+
+// collection returns the declared collection with the name, or the one
+// defined over HTTP.
+func (uc CollectionUseCase) collection(ctx context.Context, name string) (domain.Collection, error) {
+	if collection, ok := uc.declared[name]; ok {
+		return collection, nil
+	}
+
+	collection, err := uc.repo.SelectCollection(ctx, name)
+	if errors.Is(err, domain.ErrCollectionNotFound) {
+		return domain.Collection{}, fmt.Errorf("%w: %s", domain.ErrCollectionNotFound, name)
+	}
+	return collection, err
+}
+
+// fullKey returns the collection and the key parsed from the text of every
+// part.
+func (uc CollectionUseCase) fullKey(ctx context.Context, name string, texts []string) (domain.Collection, []any, error) {
+	collection, err := uc.collection(ctx, name)
+	if err != nil {
+		return domain.Collection{}, nil, err
+	}
+	if len(texts) != len(collection.Key) {
+		return domain.Collection{}, nil, fmt.Errorf("%w: collection %s has a key of %d parts, got %d",
+			domain.ErrInvalidKey, name, len(collection.Key), len(texts))
+	}
+	key, err := collection.ParseKey(texts)
+	return collection, key, err
+}
+
+func (uc CollectionUseCase) Get(ctx context.Context, name string, key []string) (domain.Item, error) {
+	collection, parsed, err := uc.fullKey(ctx, name, key)
+	if err != nil {
+		return domain.Item{}, err
+	}
+	return uc.repo.SelectItem(ctx, collection, parsed)
+}
+
+// Insert stores value under a new key and returns the stored item.
+func (uc CollectionUseCase) Insert(ctx context.Context, name string, key []string, value map[string]any) (domain.Item, error) {
+	collection, parsed, err := uc.fullKey(ctx, name, key)
+	if err != nil {
+		return domain.Item{}, err
+	}
+	if value == nil {
+		value = map[string]any{}
+	}
+
+	item := domain.Item{Key: parsed, Value: value}
+	return item, uc.repo.InsertItem(ctx, collection, item)
+}
+
+// Put stores value under the key, replacing the current one, and returns
+// the stored item.
+func (uc CollectionUseCase) Put(ctx context.Context, name string, key []string, value map[string]any) (domain.Item, error) {
+	collection, parsed, err := uc.fullKey(ctx, name, key)
+	if err != nil {
+		return domain.Item{}, err
+	}
+	if value == nil {
+		value = map[string]any{}
+	}
+
+	item := domain.Item{Key: parsed, Value: value}
+	return item, uc.repo.ReplaceItem(ctx, collection, item)
+}
+
+func (uc CollectionUseCase) Delete(ctx context.Context, name string, key []string) (domain.Item, error) {
+	collection, parsed, err := uc.fullKey(ctx, name, key)
+	if err != nil {
+		return domain.Item{}, err
+	}
+	return uc.repo.DeleteItem(ctx, collection, parsed)
+}
+
+// Scan returns one page of the items whose key starts with the parts of
+// prefix, all items when empty, in the order of the key: numerically for
+// integer parts, part by part for composite keys. The next page starts
+// after the full key of the last item.
+func (uc CollectionUseCase) Scan(ctx context.Context, name string, prefix, after []string, limit int) ([]domain.Item, error) {
+	collection, err := uc.collection(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultScanLimit
+	case limit < 0 || limit > MaxScanLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidScan, MaxScanLimit)
+	}
+
+	parsedPrefix, err := collection.ParseKey(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedAfter []any
+	if len(after) > 0 {
+		if len(after) != len(collection.Key) {
+			return nil, fmt.Errorf("%w: after must be a full key of %d parts", ErrInvalidScan, len(collection.Key))
+		}
+		if parsedAfter, err = collection.ParseKey(after); err != nil {
+			return nil, err
+		}
+		for i, part := range parsedPrefix {
+			if parsedAfter[i] != part {
+				return nil, fmt.Errorf("%w: after must start with the prefix", ErrInvalidScan)
+			}
+		}
+	}
+
+	return uc.repo.ScanItems(ctx, collection, parsedPrefix, parsedAfter, limit)
+}
+
+// Query selects the items whose path indexed by the secondary index equals
+// eq, parsed by the type of the index.
+func (uc CollectionUseCase) Query(ctx context.Context, name, index, eq string, limit int, cursor string) (domain.ItemPage, error) {
+	collection, err := uc.collection(ctx, name)
+	if err != nil {
+		return domain.ItemPage{}, err
+	}
+
+	secondary, ok := collection.Index(index)
+	if !ok {
+		return domain.ItemPage{}, fmt.Errorf("%w: collection %s has no index %q", ErrInvalidQuery, name, index)
+	}
+
+	key, err := parseIndexKey(secondary.Type, eq)
+	if err != nil {
+		return domain.ItemPage{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultQueryLimit
+	case limit < 0 || limit > MaxQueryLimit:
+		return domain.ItemPage{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxQueryLimit)
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.ItemPage{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	return uc.repo.SelectItemsByIndex(ctx, collection, domain.Query{Index: secondary.Name, Key: key, Limit: limit, After: after})
+}
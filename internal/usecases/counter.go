@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+var ErrInvalidCounter = errors.New("400 invalid counter")
+
+// CounterUseCase updates numbers inside values atomically, so concurrent
+// clients do not lose updates the way a read followed by a write does.
+type CounterUseCase struct {
+	repo      interfaces.CounterRepository
+	validator interfaces.Validator
+	log       interfaces.Logger
+}
+
+var _ interfaces.CounterUseCase = CounterUseCase{} // CounterUseCase must satisfy interfaces.CounterUseCase
+
+func NewCounterUseCase(repo interfaces.CounterRepository, validator interfaces.Validator, log interfaces.Logger) CounterUseCase {
+	return CounterUseCase{repo: repo, validator: validator, log: log}
+}
+
+// Increment adds delta to the number at path, a dotted path inside the
+// value optionally prefixed with "value.", and returns the new number.
+// Missing keys and paths are created starting from zero. The resulting
+// value must satisfy the schema of the key.
+func (uc CounterUseCase) Increment(ctx context.Context, key, path string, delta float64, min, max *float64) (any, error) {
+	if err := domain.ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	segments, err := parseCounterPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range []*float64{&delta, min, max} {
+		if f != nil && (math.IsNaN(*f) || math.IsInf(*f, 0)) {
+			return nil, fmt.Errorf("%w: numbers must be finite", ErrInvalidCounter)
+		}
+	}
+	if min != nil && max != nil && *min > *max {
+		return nil, fmt.Errorf("%w: min is greater than max", ErrInvalidCounter)
+	}
+
+	inc := domain.Increment{
+		Key:   key,
+		Path:  segments,
+		Delta: delta,
+		Min:   min,
+		Max:   max,
+	}
+	if uc.validator.Covers(key) {
+		inc.Validate = func(value map[string]any) error {
+			return uc.validator.Validate(key, value)
+		}
+	}
+
+	return uc.repo.Increment(ctx, inc)
+}
+
+func parseCounterPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(path, "value.")
+	if path == "" {
+		return nil, fmt.Errorf("%w: path is required", ErrInvalidCounter)
+	}
+
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" || strings.ContainsAny(segment, `"[]`) {
+			return nil, fmt.Errorf("%w: invalid path %q", ErrInvalidCounter, path)
+		}
+	}
+	return segments, nil
+}
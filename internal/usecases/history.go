@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+var ErrInvalidHistoryQuery = errors.New("400 invalid history query")
+
+// HistoryUseCase reads and restores the versions Tarantool keeps along
+// with every change of a stored value.
+type HistoryUseCase struct {
+	repo      interfaces.HistoryRepository
+	validator interfaces.Validator
+	log       interfaces.Logger
+}
+
+var _ interfaces.HistoryUseCase = HistoryUseCase{} // HistoryUseCase must satisfy interfaces.HistoryUseCase
+
+func NewHistoryUseCase(repo interfaces.HistoryRepository, validator interfaces.Validator, log interfaces.Logger) HistoryUseCase {
+	return HistoryUseCase{repo: repo, validator: validator, log: log}
+}
+
+// Versions returns one page of versions of key, newest first. The next
+// page starts before the number of the last version.
+func (uc HistoryUseCase) Versions(ctx context.Context, key string, before uint64, limit int) ([]domain.Version, error) {
+	switch {
+	case limit == 0:
+		limit = DefaultHistoryLimit
+	case limit < 0 || limit > MaxHistoryLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidHistoryQuery, MaxHistoryLimit)
+	}
+
+	return uc.repo.SelectVersions(ctx, key, before, limit)
+}
+
+func (uc HistoryUseCase) Version(ctx context.Context, key string, version uint64) (domain.Version, error) {
+	return uc.repo.SelectVersion(ctx, key, version)
+}
+
+// Restore makes a version of key its current value, provided the value
+// still satisfies the schema of the key, and returns the version recorded
+// for it.
+func (uc HistoryUseCase) Restore(ctx context.Context, key string, version uint64) (domain.Version, error) {
+	old, err := uc.repo.SelectVersion(ctx, key, version)
+	if err != nil {
+		return domain.Version{}, err
+	}
+	if !old.Deleted {
+		if err := uc.validator.Validate(key, old.Value); err != nil {
+			return domain.Version{}, err
+		}
+	}
+
+	return uc.repo.RestoreVersion(ctx, key, version)
+}
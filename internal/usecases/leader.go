@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"sync/atomic"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+)
+
+// releaseTimeout bounds the release of a lease after the context is done.
+const releaseTimeout = time.Second
+
+// LeaderElection elects a single owner among the instances campaigning
+// for the same lock. The lease is renewed every third of its TTL.
+type LeaderElection struct {
+	locks  interfaces.LockUseCase
+	log    interfaces.Logger
+	name   string
+	owner  string
+	ttl    time.Duration
+	leader *atomic.Bool
+}
+
+var _ interfaces.Leader = LeaderElection{} // LeaderElection must satisfy interfaces.Leader
+
+func NewLeaderElection(locks interfaces.LockUseCase, name, owner string, ttl time.Duration, log interfaces.Logger) LeaderElection {
+	return LeaderElection{locks: locks, log: log, name: name, owner: owner, ttl: ttl, leader: new(atomic.Bool)}
+}
+
+// IsLeader reports whether the lease was held at the last renewal.
+func (le LeaderElection) IsLeader() bool {
+	return le.leader.Load()
+}
+
+// Run campaigns for leadership and calls lead, if not nil, while elected.
+// The context passed to lead is cancelled once the lease is lost; the
+// election is then resumed. Run blocks until ctx is cancelled or lead
+// returns on its own.
+func (le LeaderElection) Run(ctx context.Context, lead func(context.Context)) {
+	ticker := time.NewTicker(le.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		lock, err := le.locks.Acquire(ctx, le.name, le.owner, le.ttl)
+		if err == nil {
+			utils.LoggerFromContext(ctx, le.log).Info("Elected as leader",
+				"lock", le.name,
+				"owner", le.owner,
+				"token", lock.Token,
+			)
+			if finished := le.hold(ctx, lock, lead); finished {
+				return
+			}
+		} else {
+			utils.LoggerFromContext(ctx, le.log).Debug("Leadership not acquired",
+				"lock", le.name,
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hold renews the lease until it is lost, ctx is cancelled or lead returns.
+// It reports whether the election is over.
+func (le LeaderElection) hold(ctx context.Context, lock domain.Lock, lead func(context.Context)) bool {
+	le.leader.Store(true)
+	defer le.leader.Store(false)
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if lead != nil {
+			lead(leadCtx)
+		} else {
+			<-leadCtx.Done()
+		}
+	}()
+
+	ticker := time.NewTicker(le.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			le.release(lock)
+			return leadCtx.Err() == nil || ctx.Err() != nil
+		case <-ctx.Done():
+			<-done
+			le.release(lock)
+			return true
+		case <-ticker.C:
+			renewed, err := le.locks.Renew(ctx, le.name, le.owner, lock.Token, le.ttl)
+			if err != nil {
+				utils.LoggerFromContext(ctx, le.log).Warn("Leadership lost",
+					"lock", le.name,
+					"owner", le.owner,
+					"error", err,
+				)
+				cancel()
+				<-done
+				return false
+			}
+			lock = renewed
+		}
+	}
+}
+
+func (le LeaderElection) release(lock domain.Lock) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	if _, err := le.locks.Release(ctx, le.name, le.owner, lock.Token); err != nil {
+		le.log.Debug("Failed to release leadership",
+			"lock", le.name,
+			"error", err,
+		)
+	}
+}
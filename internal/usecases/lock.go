@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"time"
+)
+
+var ErrInvalidLock = errors.New("400 invalid lock request")
+
+// LockUseCase hands out leases on named locks. Mutual exclusion, expiry and
+// fencing tokens are enforced atomically by Tarantool.
+type LockUseCase struct {
+	repo     interfaces.LockRepository
+	log      interfaces.Logger
+	settings *atomic.Pointer[config.LocksConfig]
+}
+
+var _ interfaces.LockUseCase = LockUseCase{} // LockUseCase must satisfy interfaces.LockUseCase
+
+func NewLockUseCase(repo interfaces.LockRepository, cfg config.LocksConfig, log interfaces.Logger) LockUseCase {
+	uc := LockUseCase{repo: repo, log: log, settings: new(atomic.Pointer[config.LocksConfig])}
+	uc.Reload(cfg)
+	return uc
+}
+
+// Reload replaces the lease bounds for subsequent requests. The leader
+// lease is read once by LeaderElection and does not change.
+func (uc LockUseCase) Reload(cfg config.LocksConfig) {
+	uc.settings.Store(&cfg)
+}
+
+// Acquire takes the lock for owner. A zero ttl means the default lease.
+func (uc LockUseCase) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (domain.Lock, error) {
+	ttl, err := uc.lease(name, owner, ttl)
+	if err != nil {
+		return domain.Lock{}, err
+	}
+	return uc.repo.AcquireLock(ctx, name, owner, ttl)
+}
+
+// Renew extends the lease of a lock held by owner with the given token.
+func (uc LockUseCase) Renew(ctx context.Context, name, owner string, token uint64, ttl time.Duration) (domain.Lock, error) {
+	ttl, err := uc.lease(name, owner, ttl)
+	if err != nil {
+		return domain.Lock{}, err
+	}
+	return uc.repo.RenewLock(ctx, name, owner, token, ttl)
+}
+
+func (uc LockUseCase) Release(ctx context.Context, name, owner string, token uint64) (domain.Lock, error) {
+	if _, err := uc.lease(name, owner, 0); err != nil {
+		return domain.Lock{}, err
+	}
+	return uc.repo.ReleaseLock(ctx, name, owner, token)
+}
+
+func (uc LockUseCase) lease(name, owner string, ttl time.Duration) (time.Duration, error) {
+	cfg := uc.settings.Load()
+	switch {
+	case name == "":
+		return 0, fmt.Errorf("%w: name is required", ErrInvalidLock)
+	case owner == "":
+		return 0, fmt.Errorf("%w: owner is required", ErrInvalidLock)
+	case ttl == 0:
+		return cfg.DefaultTTL, nil
+	case ttl < time.Millisecond || ttl > cfg.MaxTTL:
+		return 0, fmt.Errorf("%w: ttl must be between 1ms and %s", ErrInvalidLock, cfg.MaxTTL)
+	}
+	return ttl, nil
+}
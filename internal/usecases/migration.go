@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+)
+
+var ErrSchemaBehind = errors.New("schema is behind")
+
+type MigrationUseCase struct {
+	repo     interfaces.MigrationRepository
+	log      interfaces.Logger
+	required uint64
+}
+
+var _ interfaces.MigrationUseCase = MigrationUseCase{} // MigrationUseCase must satisfy interfaces.MigrationUseCase
+
+// NewMigrationUseCase returns migrations of the repository, required being
+// the version the application relies on.
+func NewMigrationUseCase(repo interfaces.MigrationRepository, required uint64, log interfaces.Logger) MigrationUseCase {
+	return MigrationUseCase{repo: repo, log: log, required: required}
+}
+
+func (uc MigrationUseCase) Status(ctx context.Context) ([]domain.Migration, error) {
+	return uc.repo.MigrationStatus(ctx)
+}
+
+// Version returns the newest applied migration, zero on an empty instance.
+func (uc MigrationUseCase) Version(ctx context.Context) (uint64, error) {
+	migrations, err := uc.repo.MigrationStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version := uint64(0)
+	for _, migration := range migrations {
+		if migration.Applied() {
+			version = max(version, migration.Version)
+		}
+	}
+	return version, nil
+}
+
+// Up applies pending migrations up to target, every one when target is zero.
+func (uc MigrationUseCase) Up(ctx context.Context, target uint64) ([]domain.Migration, error) {
+	applied, err := uc.repo.MigrateUp(ctx, target)
+	for _, migration := range applied {
+		utils.LoggerFromContext(ctx, uc.log).Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	return applied, err
+}
+
+// Down reverts applied migrations newer than target.
+func (uc MigrationUseCase) Down(ctx context.Context, target uint64) ([]domain.Migration, error) {
+	reverted, err := uc.repo.MigrateDown(ctx, target)
+	for _, migration := range reverted {
+		utils.LoggerFromContext(ctx, uc.log).Info("Reverted migration", "version", migration.Version, "name", migration.Name)
+	}
+	return reverted, err
+}
+
+// Check returns ErrSchemaBehind when a migration the application relies on
+// is pending.
+func (uc MigrationUseCase) Check(ctx context.Context) error {
+	migrations, err := uc.repo.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []uint64
+	latest := uint64(0)
+	for _, migration := range migrations {
+		latest = max(latest, migration.Version)
+		if migration.Version <= uc.required && !migration.Applied() {
+			pending = append(pending, migration.Version)
+		}
+	}
+
+	if latest < uc.required {
+		return fmt.Errorf("%w: storage knows migrations up to %d, %d required", ErrSchemaBehind, latest, uc.required)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %v", ErrSchemaBehind, pending)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+)
+
+const (
+	DefaultQueryLimit = 50
+	MaxQueryLimit     = 1000
+)
+
+var ErrInvalidQuery = errors.New("400 invalid query")
+
+// QueryUseCase resolves query fields to the declared secondary indexes.
+type QueryUseCase struct {
+	repo    interfaces.QueryRepository
+	log     interfaces.Logger
+	indexes map[string]config.IndexConfig // by path
+}
+
+var _ interfaces.QueryUseCase = QueryUseCase{} // QueryUseCase must satisfy interfaces.QueryUseCase
+
+func NewQueryUseCase(repo interfaces.QueryRepository, indexes []config.IndexConfig, log interfaces.Logger) QueryUseCase {
+	byPath := make(map[string]config.IndexConfig, len(indexes))
+	for _, index := range indexes {
+		byPath[strings.TrimSuffix(index.Path, "[*]")] = index
+	}
+	return QueryUseCase{repo: repo, log: log, indexes: byPath}
+}
+
+// Query selects values whose field equals eq. Field is a path inside the
+// value, optionally prefixed with "value.", e.g. "value.status".
+func (uc QueryUseCase) Query(ctx context.Context, field, eq string, limit int, cursor string) (domain.Page, error) {
+	path := strings.TrimPrefix(field, "value.")
+	index, ok := uc.indexes[path]
+	if !ok {
+		return domain.Page{}, fmt.Errorf("%w: field %q is not indexed", ErrInvalidQuery, field)
+	}
+
+	key, err := parseIndexKey(index.Type, eq)
+	if err != nil {
+		return domain.Page{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultQueryLimit
+	case limit < 0 || limit > MaxQueryLimit:
+		return domain.Page{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxQueryLimit)
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.Page{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	return uc.repo.SelectByIndex(ctx, domain.Query{Index: index.Name, Key: key, Limit: limit, After: after})
+}
+
+// Reindex backfills the indexed projection of values written before the
+// indexes were declared.
+func (uc QueryUseCase) Reindex(ctx context.Context) {
+	if len(uc.indexes) == 0 {
+		return
+	}
+
+	updated, err := uc.repo.Reindex(ctx, DefaultQueryLimit)
+	if err != nil {
+		utils.LoggerFromContext(ctx, uc.log).Error("Reindex failed",
+			"updated", updated,
+			"error", err,
+		)
+		return
+	}
+	if updated > 0 {
+		utils.LoggerFromContext(ctx, uc.log).Info("Reindexed values",
+			"updated", updated,
+		)
+	}
+}
+
+func parseIndexKey(kind, raw string) (any, error) {
+	switch kind {
+	case "unsigned":
+		return strconv.ParseUint(raw, 10, 64)
+	case "integer":
+		return strconv.ParseInt(raw, 10, 64)
+	case "number":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
+
+// EncodeCursor returns the opaque cursor for the position of the next page.
+func EncodeCursor(next []byte) string {
+	return base64.RawURLEncoding.EncodeToString(next)
+}
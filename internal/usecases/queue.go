@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"time"
+)
+
+const (
+	DefaultPeekLimit = 10
+	MaxPeekLimit     = 1000
+)
+
+var ErrInvalidTask = errors.New("400 invalid task request")
+
+// QueueUseCase runs the work queues with at-least-once delivery: a task
+// taken and not acknowledged within its visibility timeout is delivered
+// again, until it is buried after the configured number of attempts.
+type QueueUseCase struct {
+	repo     interfaces.QueueRepository
+	log      interfaces.Logger
+	settings *atomic.Pointer[config.QueuesConfig]
+}
+
+var _ interfaces.QueueUseCase = QueueUseCase{} // QueueUseCase must satisfy interfaces.QueueUseCase
+
+func NewQueueUseCase(repo interfaces.QueueRepository, cfg config.QueuesConfig, log interfaces.Logger) QueueUseCase {
+	uc := QueueUseCase{repo: repo, log: log, settings: new(atomic.Pointer[config.QueuesConfig])}
+	uc.Reload(cfg)
+	return uc
+}
+
+// Reload replaces the settings of the queues for subsequent requests.
+func (uc QueueUseCase) Reload(cfg config.QueuesConfig) {
+	uc.settings.Store(&cfg)
+}
+
+// Put adds a task that becomes visible after delay.
+func (uc QueueUseCase) Put(ctx context.Context, queue string, payload any, delay time.Duration) (domain.Task, error) {
+	if queue == "" {
+		return domain.Task{}, fmt.Errorf("%w: queue name is required", ErrInvalidTask)
+	}
+	if delay < 0 {
+		return domain.Task{}, fmt.Errorf("%w: delay must not be negative", ErrInvalidTask)
+	}
+	return uc.repo.PutTask(ctx, queue, payload, delay)
+}
+
+// Take hides the next due task for visibility, the configured default when
+// zero, waiting up to wait for one to appear.
+func (uc QueueUseCase) Take(ctx context.Context, queue string, visibility, wait time.Duration) (domain.Task, error) {
+	cfg := uc.settings.Load()
+	switch {
+	case visibility == 0:
+		visibility = cfg.Visibility
+	case visibility < time.Millisecond || visibility > cfg.MaxVisibility:
+		return domain.Task{}, fmt.Errorf("%w: visibility must be between 1ms and %s", ErrInvalidTask, cfg.MaxVisibility)
+	}
+	if wait < 0 {
+		return domain.Task{}, fmt.Errorf("%w: wait must not be negative", ErrInvalidTask)
+	}
+
+	return uc.repo.TakeTask(ctx, queue, visibility, cfg.MaxAttempts, min(wait, cfg.MaxWait))
+}
+
+// Ack removes a task taken with the receipt, the attempt number returned
+// by Take.
+func (uc QueueUseCase) Ack(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error) {
+	return uc.repo.AckTask(ctx, queue, id, receipt)
+}
+
+// Nack returns a task taken with the receipt to the queue after delay, or
+// buries it when it has used up its attempts.
+func (uc QueueUseCase) Nack(ctx context.Context, queue string, id, receipt uint64, delay time.Duration) (domain.Task, error) {
+	if delay < 0 {
+		return domain.Task{}, fmt.Errorf("%w: delay must not be negative", ErrInvalidTask)
+	}
+	return uc.repo.NackTask(ctx, queue, id, receipt, delay, uc.settings.Load().MaxAttempts)
+}
+
+// Bury moves a task taken with the receipt to the dead letters.
+func (uc QueueUseCase) Bury(ctx context.Context, queue string, id, receipt uint64) (domain.Task, error) {
+	return uc.repo.BuryTask(ctx, queue, id, receipt)
+}
+
+// Peek lists tasks with the status, ready by default, without taking them.
+func (uc QueueUseCase) Peek(ctx context.Context, queue, status string, limit int) ([]domain.Task, error) {
+	switch status {
+	case "":
+		status = domain.TaskReady
+	case domain.TaskReady, domain.TaskTaken, domain.TaskBuried:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTask, status)
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultPeekLimit
+	case limit < 0 || limit > MaxPeekLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTask, MaxPeekLimit)
+	}
+
+	return uc.repo.PeekTasks(ctx, queue, status, limit)
+}
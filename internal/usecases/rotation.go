@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"tarantool-app/config"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+)
+
+// KeyRotation periodically reloads the keyring and re-encrypts values
+// sealed with keys other than the active one. Every instance reloads the
+// keyring, only the leader re-encrypts.
+type KeyRotation struct {
+	repo      interfaces.KeyRotationRepository
+	leader    interfaces.Leader
+	log       interfaces.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+func NewKeyRotation(repo interfaces.KeyRotationRepository, cfg config.EncryptionConfig, leader interfaces.Leader, log interfaces.Logger) KeyRotation {
+	return KeyRotation{repo: repo, leader: leader, log: log, interval: cfg.RotationInterval, batchSize: cfg.RotationBatch}
+}
+
+// Run blocks until ctx is cancelled.
+func (kr KeyRotation) Run(ctx context.Context) {
+	ticker := time.NewTicker(kr.interval)
+	defer ticker.Stop()
+
+	for {
+		kr.rotate(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (kr KeyRotation) rotate(ctx context.Context) {
+	changed, err := kr.repo.ReloadKeyring(ctx)
+	if err != nil {
+		utils.LoggerFromContext(ctx, kr.log).Error("Failed to reload keyring",
+			"error", err,
+		)
+	} else if changed {
+		utils.LoggerFromContext(ctx, kr.log).Info("Keyring reloaded")
+	}
+
+	if !kr.leader.IsLeader() {
+		return
+	}
+
+	rotated, err := kr.repo.RotateKeys(ctx, kr.batchSize)
+	if err != nil {
+		utils.LoggerFromContext(ctx, kr.log).Error("Key rotation failed",
+			"rotated", rotated,
+			"error", err,
+		)
+		return
+	}
+	if rotated > 0 {
+		utils.LoggerFromContext(ctx, kr.log).Info("Values re-encrypted with the active key",
+			"rotated", rotated,
+		)
+	}
+}
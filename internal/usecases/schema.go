@@ -0,0 +1,258 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+type compiledSchema struct {
+	source domain.Schema
+	schema *jsonschema.Schema
+}
+
+// SchemaRegistry keeps compiled JSON Schemas from the config file and from
+// the `kv_schemas` space, and validates values by the longest key prefix.
+type SchemaRegistry struct {
+	repo     interfaces.SchemaRepository
+	log      interfaces.Logger
+	interval time.Duration
+
+	static []compiledSchema
+
+	mu      sync.RWMutex
+	schemas map[string]compiledSchema
+}
+
+var (
+	_ interfaces.SchemaUseCase = &SchemaRegistry{} // SchemaRegistry must satisfy interfaces.SchemaUseCase
+	_ interfaces.Validator     = &SchemaRegistry{} // SchemaRegistry must satisfy interfaces.Validator
+)
+
+func NewSchemaRegistry(repo interfaces.SchemaRepository, cfg config.ValidationConfig, log interfaces.Logger) (*SchemaRegistry, error) {
+	sr := &SchemaRegistry{repo: repo, log: log, interval: cfg.RefreshInterval}
+
+	for _, file := range cfg.Schemas {
+		data, err := os.ReadFile(file.File)
+		if err != nil {
+			return nil, err
+		}
+
+		var document map[string]any
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("schema file %q: %w", file.File, err)
+		}
+
+		compiled, err := compileSchema(domain.Schema{Prefix: file.Prefix, Document: document})
+		if err != nil {
+			return nil, fmt.Errorf("schema file %q: %w", file.File, err)
+		}
+		sr.static = append(sr.static, compiled)
+	}
+
+	sr.schemas = sr.merge(nil)
+	return sr, nil
+}
+
+// Run periodically reloads the schemas stored in Tarantool, picking up
+// registrations made through other instances. It blocks until ctx is done.
+func (sr *SchemaRegistry) Run(ctx context.Context) {
+	ticker := time.NewTicker(sr.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sr.Refresh(ctx); err != nil {
+			utils.LoggerFromContext(ctx, sr.log).Warn("Failed to refresh JSON schemas",
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (sr *SchemaRegistry) Refresh(ctx context.Context) error {
+	stored, err := sr.repo.SelectSchemas(ctx)
+	if err != nil {
+		return err
+	}
+
+	compiled := make([]compiledSchema, 0, len(stored))
+	for _, schema := range stored {
+		c, err := compileSchema(schema)
+		if err != nil {
+			utils.LoggerFromContext(ctx, sr.log).Error("Stored JSON schema does not compile",
+				"prefix", schema.Prefix,
+				"error", err,
+			)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	schemas := sr.merge(compiled)
+
+	sr.mu.Lock()
+	sr.schemas = schemas
+	sr.mu.Unlock()
+
+	return nil
+}
+
+func (sr *SchemaRegistry) merge(stored []compiledSchema) map[string]compiledSchema {
+	schemas := make(map[string]compiledSchema, len(sr.static)+len(stored))
+	for _, c := range sr.static {
+		schemas[c.source.Prefix] = c
+	}
+	for _, c := range stored {
+		schemas[c.source.Prefix] = c
+	}
+	return schemas
+}
+
+func (sr *SchemaRegistry) List(ctx context.Context) ([]domain.Schema, error) {
+	if err := sr.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schemas := make([]domain.Schema, 0, len(sr.schemas))
+	for _, c := range sr.schemas {
+		schemas = append(schemas, c.source)
+	}
+	return schemas, nil
+}
+
+func (sr *SchemaRegistry) Register(ctx context.Context, schema domain.Schema) error {
+	if _, err := compileSchema(schema); err != nil {
+		return err
+	}
+
+	if err := sr.repo.ReplaceSchema(ctx, schema); err != nil {
+		return err
+	}
+
+	return sr.Refresh(ctx)
+}
+
+func (sr *SchemaRegistry) Unregister(ctx context.Context, prefix string) (domain.Schema, error) {
+	schema, err := sr.repo.DeleteSchema(ctx, prefix)
+	if err != nil {
+		return domain.Schema{}, err
+	}
+
+	return schema, sr.Refresh(ctx)
+}
+
+// Covers reports whether a schema applies to the key.
+func (sr *SchemaRegistry) Covers(key string) bool {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	for prefix := range sr.schemas {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks value against the schema of the longest matching prefix.
+// Keys matching no prefix are not validated.
+func (sr *SchemaRegistry) Validate(key string, value map[string]any) error {
+	sr.mu.RLock()
+	var match *compiledSchema
+	for prefix, c := range sr.schemas {
+		if strings.HasPrefix(key, prefix) && (match == nil || len(prefix) > len(match.source.Prefix)) {
+			match = &c
+		}
+	}
+	sr.mu.RUnlock()
+
+	if match == nil {
+		return nil
+	}
+
+	instance, err := toJSONValue(value)
+	if err != nil {
+		return err
+	}
+
+	err = match.schema.Validate(instance)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		return &domain.ValidationError{
+			Prefix:     match.source.Prefix,
+			Violations: violations(validationErr),
+		}
+	}
+	return err
+}
+
+func compileSchema(schema domain.Schema) (compiledSchema, error) {
+	document, err := toJSONValue(schema.Document)
+	if err != nil {
+		return compiledSchema{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	location := "mem://schemas/" + url.PathEscape(schema.Prefix)
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(location, document); err != nil {
+		return compiledSchema{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	compiled, err := compiler.Compile(location)
+	if err != nil {
+		return compiledSchema{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	return compiledSchema{source: schema, schema: compiled}, nil
+}
+
+// toJSONValue normalizes values decoded from msgpack or CBOR into the
+// types produced by a JSON decoder.
+func toJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
+
+// violations flattens the validation error tree into its leaf errors.
+func violations(err *jsonschema.ValidationError) []domain.Violation {
+	var result []domain.Violation
+	for _, unit := range err.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		path := unit.InstanceLocation
+		if path == "" {
+			path = "/"
+		}
+		result = append(result, domain.Violation{Path: path, Message: unit.Error.String()})
+	}
+	return result
+}
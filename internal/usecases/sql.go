@@ -0,0 +1,213 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"unicode"
+)
+
+var (
+	ErrNotReadOnly = errors.New("400 only a single SELECT statement is allowed")
+	ErrTieredSpace = errors.New("400 kv_storage holds the keys in memory only while tiering is enabled")
+)
+
+// tieredSpaces hold part of the keys while tiering is enabled, the others
+// being demoted to kv_cold.
+var tieredSpaces = map[string]bool{"kv_storage": true}
+
+// Keywords that may start a read-only statement.
+var readOnlyLeadingKeywords = map[string]bool{
+	"SELECT": true,
+	"VALUES": true,
+	"WITH":   true,
+}
+
+// Keywords rejected anywhere outside literals and quoted identifiers.
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "UPSERT": true,
+	"CREATE": true, "DROP": true, "ALTER": true, "TRUNCATE": true, "REINDEX": true,
+	"PRAGMA": true, "GRANT": true, "REVOKE": true, "SET": true, "ANALYZE": true,
+	"BEGIN": true, "START": true, "COMMIT": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true,
+}
+
+// Built-in functions a statement may call. Others, e.g. LUA or Lua
+// functions exported to SQL, may change data.
+var readOnlyFunctions = map[string]bool{
+	"ABS": true, "AVG": true, "CAST": true, "CHAR": true, "CHARACTER_LENGTH": true, "CHAR_LENGTH": true,
+	"COALESCE": true, "COUNT": true, "GREATEST": true, "GROUP_CONCAT": true, "HEX": true, "IFNULL": true,
+	"LEAST": true, "LENGTH": true, "LIKELIHOOD": true, "LIKELY": true, "LOWER": true, "MAX": true, "MIN": true,
+	"NOW": true, "NULLIF": true, "POSITION": true, "PRINTF": true, "QUOTE": true, "ROUND": true, "SOUNDEX": true,
+	"SUBSTR": true, "SUBSTRING": true, "SUM": true, "TOTAL": true, "TRIM": true, "TYPEOF": true, "UNICODE": true,
+	"UNLIKELY": true, "UPPER": true, "UUID": true, "VERSION": true,
+}
+
+// Keywords that may precede an opening parenthesis without calling a
+// function.
+var parenthesisKeywords = map[string]bool{
+	"SELECT": true, "VALUES": true, "WITH": true, "AS": true, "FROM": true, "JOIN": true, "ON": true,
+	"USING": true, "WHERE": true, "HAVING": true, "BY": true, "AND": true, "OR": true, "NOT": true,
+	"IN": true, "EXISTS": true, "LIKE": true, "BETWEEN": true, "IS": true, "ESCAPE": true, "CASE": true,
+	"WHEN": true, "THEN": true, "ELSE": true, "UNION": true, "ALL": true, "EXCEPT": true, "INTERSECT": true,
+	"DISTINCT": true, "LIMIT": true, "OFFSET": true,
+}
+
+type SQLUseCase struct {
+	repo     interfaces.SQLRepository
+	log      interfaces.Logger
+	settings *atomic.Pointer[config.SQLConfig]
+	tiered   bool // whether statements over tieredSpaces are rejected
+}
+
+var _ interfaces.SQLUseCase = SQLUseCase{} // SQLUseCase must satisfy interfaces.SQLUseCase
+
+func NewSQLUseCase(repo interfaces.SQLRepository, cfg config.SQLConfig, tiering config.TieringConfig, log interfaces.Logger) SQLUseCase {
+	uc := SQLUseCase{repo: repo, log: log, settings: new(atomic.Pointer[config.SQLConfig]), tiered: tiering.Enabled}
+	uc.Reload(cfg)
+	return uc
+}
+
+// Reload replaces the limits of subsequent statements.
+func (uc SQLUseCase) Reload(cfg config.SQLConfig) {
+	uc.settings.Store(&cfg)
+}
+
+// Query runs a read-only statement with bound parameters. The row limit is
+// capped by the configured maximum, the statement is abandoned after the
+// configured timeout and stopped by Tarantool after the configured steps.
+// While tiering is enabled, statements naming a tiered space are rejected:
+// they would miss the demoted keys.
+func (uc SQLUseCase) Query(ctx context.Context, statement string, params []any, limit int) (domain.SQLResult, error) {
+	statement, identifiers, err := readOnlyStatement(statement)
+	if err != nil {
+		return domain.SQLResult{}, err
+	}
+	if uc.tiered {
+		for _, identifier := range identifiers {
+			if tieredSpaces[identifier] {
+				return domain.SQLResult{}, fmt.Errorf("%w: query kv_cold for the demoted keys", ErrTieredSpace)
+			}
+		}
+	}
+
+	cfg := uc.settings.Load()
+	if limit <= 0 || limit > cfg.MaxRows {
+		limit = cfg.MaxRows
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	return uc.repo.Execute(ctx, domain.SQLQuery{Statement: statement, Params: params, Limit: limit, MaxSteps: cfg.MaxSteps})
+}
+
+// readOnlyStatement checks that the statement is a single SELECT, VALUES or
+// WITH statement with balanced parentheses, no data or schema changing
+// keywords and no calls of functions other than readOnlyFunctions, and
+// returns it without comments and the trailing semicolon, so
+// that it can be wrapped into a subquery, along with its quoted
+// identifiers. Unquoted identifiers name upper case objects, unlike the
+// spaces of the application.
+func readOnlyStatement(statement string) (string, []string, error) {
+	var keywords, identifiers []string
+	var stripped strings.Builder
+	depth, terminated := 0, false
+	callee, quoted := "", false // the name before the current token
+	runes := []rune(statement)
+
+	for i := 0; i < len(runes); i++ {
+		start := i
+		name, isQuoted := "", false
+		switch r := runes[i]; {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+			stripped.WriteRune(' ')
+			continue
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && (runes[end] != '*' || runes[end+1] != '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return "", nil, fmt.Errorf("%w: unterminated comment", ErrNotReadOnly)
+			}
+			i = end + 1
+			stripped.WriteRune(' ')
+			continue
+		case unicode.IsSpace(r):
+			stripped.WriteRune(r)
+			continue
+		case terminated:
+			return "", nil, fmt.Errorf("%w: multiple statements", ErrNotReadOnly)
+		case r == ';':
+			terminated = true
+			continue
+		case r == '\'' || r == '"':
+			end := closingQuote(runes, i)
+			if end < 0 {
+				return "", nil, fmt.Errorf("%w: unterminated quote", ErrNotReadOnly)
+			}
+			if r == '"' {
+				name, isQuoted = strings.ReplaceAll(string(runes[i+1:end]), `""`, `"`), true
+				identifiers = append(identifiers, name)
+			}
+			i = end
+		case r == '(':
+			// Quoted names are matched as they are, so "lua" is not LUA.
+			if callee != "" && !readOnlyFunctions[callee] && (quoted || !parenthesisKeywords[callee]) {
+				return "", nil, fmt.Errorf("%w: function %s is not allowed", ErrNotReadOnly, callee)
+			}
+			depth++
+		case r == ')':
+			if depth--; depth < 0 {
+				return "", nil, fmt.Errorf("%w: unbalanced parentheses", ErrNotReadOnly)
+			}
+		case r == '_' || unicode.IsLetter(r):
+			for i+1 < len(runes) && (runes[i+1] == '_' || unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1])) {
+				i++
+			}
+			name = strings.ToUpper(string(runes[start : i+1]))
+			keywords = append(keywords, name)
+		}
+		callee, quoted = name, isQuoted
+		stripped.WriteString(string(runes[start : i+1]))
+	}
+
+	if depth != 0 {
+		return "", nil, fmt.Errorf("%w: unbalanced parentheses", ErrNotReadOnly)
+	}
+	if len(keywords) == 0 || !readOnlyLeadingKeywords[keywords[0]] {
+		return "", nil, ErrNotReadOnly
+	}
+	for _, keyword := range keywords {
+		if writeKeywords[keyword] {
+			return "", nil, fmt.Errorf("%w: %s is not allowed", ErrNotReadOnly, keyword)
+		}
+	}
+
+	return strings.TrimSpace(stripped.String()), identifiers, nil
+}
+
+// closingQuote returns the index of the quote closing the one at start,
+// treating doubled quotes as escapes, or -1.
+func closingQuote(runes []rune, start int) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"tarantool-app/config"
+	"tarantool-app/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestReadOnlyStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		statement   string
+		stripped    string
+		identifiers []string
+		err         bool
+	}{
+		{"select", `SELECT 1`, `SELECT 1`, nil, false},
+		{"quoted identifiers", `SELECT "key" FROM "kv_storage"`, `SELECT "key" FROM "kv_storage"`, []string{"key", "kv_storage"}, false},
+		{"escaped quote", `SELECT * FROM "a""b"`, `SELECT * FROM "a""b"`, []string{`a"b`}, false},
+		{"string literal", `SELECT 'kv_storage'`, `SELECT 'kv_storage'`, nil, false},
+		{"comments and semicolon", "SELECT 1 -- one\n/* two */;", "SELECT 1", nil, false},
+		{"with", `WITH t AS (SELECT 1) SELECT * FROM t`, `WITH t AS (SELECT 1) SELECT * FROM t`, nil, false},
+		{"write keyword", `SELECT 1; DELETE FROM "kv_storage"`, "", nil, true},
+		{"not a select", `DELETE FROM "kv_storage"`, "", nil, true},
+		{"unbalanced parentheses", `SELECT (1`, "", nil, true},
+		{"unterminated quote", `SELECT "kv_storage`, "", nil, true},
+		{"allowed functions", `SELECT COUNT(*), upper ("key") FROM "kv_storage" WHERE "key" IN (SELECT 'a')`, `SELECT COUNT(*), upper ("key") FROM "kv_storage" WHERE "key" IN (SELECT 'a')`, []string{"key", "kv_storage", "key"}, false},
+		{"lua function", `SELECT LUA('return box.space.kv_storage:truncate()')`, "", nil, true},
+		{"lua function in lower case", `SELECT lua /* call */ ('return 1')`, "", nil, true},
+		{"quoted function", `SELECT "LUA"('return 1')`, "", nil, true},
+		{"exported Lua function", `SELECT "kv_incr"('k', 'n', 1, NULL, NULL)`, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped, identifiers, err := readOnlyStatement(tt.statement)
+			if tt.err {
+				if !errors.Is(err, ErrNotReadOnly) {
+					t.Fatalf("readOnlyStatement(%q) error %v, want ErrNotReadOnly", tt.statement, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readOnlyStatement(%q) error %v", tt.statement, err)
+			}
+			if stripped != tt.stripped || !reflect.DeepEqual(identifiers, tt.identifiers) {
+				t.Fatalf("readOnlyStatement(%q) = %q, %q, want %q, %q", tt.statement, stripped, identifiers, tt.stripped, tt.identifiers)
+			}
+		})
+	}
+}
+
+// sqlRepository answers every statement with an empty result.
+type sqlRepository struct{}
+
+func (sqlRepository) Execute(context.Context, domain.SQLQuery) (domain.SQLResult, error) {
+	return domain.SQLResult{}, nil
+}
+
+func TestSQLQueryTiered(t *testing.T) {
+	settings := config.SQLConfig{MaxRows: 10, Timeout: time.Second, MaxSteps: 1000}
+
+	tests := []struct {
+		name      string
+		tiering   bool
+		statement string
+		err       error
+	}{
+		{"tiering disabled", false, `SELECT * FROM "kv_storage"`, nil},
+		{"tiered space", true, `SELECT * FROM "kv_storage"`, ErrTieredSpace},
+		{"tiered space in a subquery", true, `SELECT * FROM "kv_locks" WHERE "name" IN (SELECT "key" FROM "kv_storage")`, ErrTieredSpace},
+		{"cold tier", true, `SELECT * FROM "kv_cold"`, nil},
+		{"space name as a literal", true, `SELECT 'kv_storage'`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewSQLUseCase(sqlRepository{}, settings, config.TieringConfig{Enabled: tt.tiering}, nil)
+			_, err := uc.Query(context.Background(), tt.statement, nil, 0)
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("Query(%q) error %v, want %v", tt.statement, err, tt.err)
+			}
+		})
+	}
+}
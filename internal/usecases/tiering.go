@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"tarantool-app/config"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+	"time"
+)
+
+// Tiering periodically sweeps the keys in memory and demotes those idle
+// for longer than the threshold to disk. Only the leader sweeps; reads and
+// writes promote demoted keys back on any instance.
+type Tiering struct {
+	repo      interfaces.TieringRepository
+	leader    interfaces.Leader
+	log       interfaces.Logger
+	idle      time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+func NewTiering(repo interfaces.TieringRepository, cfg config.TieringConfig, leader interfaces.Leader, log interfaces.Logger) Tiering {
+	return Tiering{repo: repo, leader: leader, log: log, idle: cfg.IdleAfter, interval: cfg.Interval, batchSize: cfg.Batch}
+}
+
+// Run blocks until ctx is cancelled.
+func (t Tiering) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		if t.leader.IsLeader() {
+			t.sweep(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep goes once over the keys in memory, a batch per request.
+func (t Tiering) sweep(ctx context.Context) {
+	demoted := 0
+	for after := ""; ; {
+		n, next, err := t.repo.DemoteIdle(ctx, t.idle, after, t.batchSize)
+		demoted += n
+		if err != nil {
+			utils.LoggerFromContext(ctx, t.log).Error("Demotion of idle keys failed",
+				"demoted", demoted,
+				"error", err,
+			)
+			return
+		}
+		if next == "" || ctx.Err() != nil {
+			break
+		}
+		after = next
+	}
+
+	if demoted > 0 {
+		utils.LoggerFromContext(ctx, t.log).Info("Idle keys demoted to disk",
+			"demoted", demoted,
+		)
+	}
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+	"tarantool-app/internal/utils"
+)
+
+const (
+	DefaultTransferBatch = 500
+	MaxTransferBatch     = 10000
+)
+
+var (
+	ErrInvalidTransfer = errors.New("400 invalid export or import")
+	ErrImportConflict  = errors.New("409 import stopped on an existing key")
+)
+
+// TransferUseCase streams the key space out and back in page by page, so
+// neither side holds more than one batch in memory.
+type TransferUseCase struct {
+	repo      interfaces.TransferRepository
+	validator interfaces.Validator
+	log       interfaces.Logger
+}
+
+var _ interfaces.TransferUseCase = TransferUseCase{} // TransferUseCase must satisfy interfaces.TransferUseCase
+
+func NewTransferUseCase(repo interfaces.TransferRepository, validator interfaces.Validator, log interfaces.Logger) TransferUseCase {
+	return TransferUseCase{repo: repo, validator: validator, log: log}
+}
+
+// Export writes every value whose key has the prefix to w and returns the
+// number of values written. Nothing is written for an unknown format.
+func (uc TransferUseCase) Export(ctx context.Context, w io.Writer, prefix, format string) (int, error) {
+	enc, err := newPayloadEncoder(w, format)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidTransfer, err)
+	}
+
+	exported := 0
+	for after := ""; ; {
+		page, err := uc.repo.ScanPayloads(ctx, prefix, after, DefaultTransferBatch)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, payload := range page {
+			if err := enc.Encode(payload); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+
+		if len(page) < DefaultTransferBatch {
+			return exported, enc.Flush()
+		}
+		if err := enc.Flush(); err != nil {
+			return exported, err
+		}
+		after = page[len(page)-1].Key
+	}
+}
+
+// Import reads values from r and writes them in batches according to the
+// conflict policy. With the fail policy the import stops before the first
+// batch containing an existing key and ErrImportConflict is returned along
+// with the report of the batches written so far.
+func (uc TransferUseCase) Import(ctx context.Context, r io.Reader, opts domain.ImportOptions) (domain.ImportReport, error) {
+	report := domain.ImportReport{DryRun: opts.DryRun}
+
+	switch opts.Policy {
+	case "":
+		opts.Policy = domain.ConflictSkip
+	case domain.ConflictSkip, domain.ConflictOverwrite, domain.ConflictFail:
+	default:
+		return report, fmt.Errorf("%w: unknown conflict policy %q", ErrInvalidTransfer, opts.Policy)
+	}
+
+	switch {
+	case opts.BatchSize == 0:
+		opts.BatchSize = DefaultTransferBatch
+	case opts.BatchSize < 0 || opts.BatchSize > MaxTransferBatch:
+		return report, fmt.Errorf("%w: batch size must be between 1 and %d", ErrInvalidTransfer, MaxTransferBatch)
+	}
+
+	dec, err := newPayloadDecoder(r, opts.Format)
+	if err != nil {
+		return report, fmt.Errorf("%w: %v", ErrInvalidTransfer, err)
+	}
+
+	batch := make([]domain.Payload, 0, opts.BatchSize)
+	for {
+		payload, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: %v", ErrInvalidTransfer, err)
+		}
+
+		payload.Key = domain.NormalizeKey(payload.Key)
+		if err := domain.ValidateKey(payload.Key); err != nil {
+			report.AddFailure(payload.Key, err.Error())
+			continue
+		}
+		if payload.Value == nil {
+			report.AddFailure(payload.Key, "value must be an object")
+			continue
+		}
+		if err := uc.validator.Validate(payload.Key, payload.Value); err != nil {
+			report.AddFailure(payload.Key, err.Error())
+			continue
+		}
+
+		if batch = append(batch, payload); len(batch) == opts.BatchSize {
+			if err := uc.importBatch(ctx, batch, opts, &report); err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := uc.importBatch(ctx, batch, opts, &report); err != nil {
+			return report, err
+		}
+	}
+
+	utils.LoggerFromContext(ctx, uc.log).Info("Import finished",
+		"inserted", report.Inserted,
+		"overwritten", report.Overwritten,
+		"skipped", report.Skipped,
+		"failed", report.Failed,
+		"dry_run", report.DryRun,
+	)
+	return report, nil
+}
+
+func (uc TransferUseCase) importBatch(ctx context.Context, batch []domain.Payload, opts domain.ImportOptions, report *domain.ImportReport) error {
+	keys := make([]string, len(batch))
+	for i, payload := range batch {
+		keys[i] = payload.Key
+	}
+
+	existing, err := uc.repo.ExistingKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	writes := make([]domain.Payload, 0, len(batch))
+	for _, payload := range batch {
+		switch {
+		case !existing[payload.Key]:
+			writes = append(writes, payload)
+		case opts.Policy == domain.ConflictOverwrite:
+			writes = append(writes, payload)
+		case opts.Policy == domain.ConflictSkip:
+			report.Skipped++
+		default:
+			report.AddFailure(payload.Key, "key already exists")
+			return ErrImportConflict
+		}
+	}
+
+	if opts.DryRun {
+		for _, payload := range writes {
+			report.AddWritten(existing[payload.Key])
+		}
+		return nil
+	}
+
+	errs := uc.repo.WriteBatch(ctx, writes, opts.Policy == domain.ConflictOverwrite)
+	for i, err := range errs {
+		if err != nil {
+			report.AddFailure(writes[i].Key, err.Error())
+		} else {
+			report.AddWritten(existing[writes[i].Key])
+		}
+	}
+	return nil
+}
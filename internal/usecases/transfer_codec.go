@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"tarantool-app/internal/domain"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// payloadEncoder writes payloads in one of the export formats.
+type payloadEncoder interface {
+	Encode(domain.Payload) error
+	Flush() error
+}
+
+// payloadDecoder reads payloads until io.EOF. A value that is not an
+// object is returned as a nil Value, so it fails on its own line instead of
+// stopping the import.
+type payloadDecoder interface {
+	Decode() (domain.Payload, error)
+}
+
+// transferRecord is a decoded record of any of the formats, whose value may
+// be of any type.
+type transferRecord struct {
+	_msgpack struct{} `msgpack:",as_array"` //nolint:unused
+	Key      string   `json:"key"`
+	Value    any      `json:"value"`
+}
+
+func (r transferRecord) payload() domain.Payload {
+	value, _ := normalizeNumbers(r.Value).(map[string]any)
+	return domain.Payload{Key: r.Key, Value: value}
+}
+
+func newPayloadEncoder(w io.Writer, format string) (payloadEncoder, error) {
+	switch format {
+	case domain.FormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return ndjsonEncoder{w: bw, enc: json.NewEncoder(bw)}, nil
+	case domain.FormatCSV:
+		enc := csvEncoder{w: csv.NewWriter(w)}
+		return enc, enc.w.Write([]string{"key", "value"})
+	case domain.FormatMsgpack:
+		bw := bufio.NewWriter(w)
+		return msgpackEncoder{w: bw, enc: msgpack.NewEncoder(bw)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func newPayloadDecoder(r io.Reader, format string) (payloadDecoder, error) {
+	switch format {
+	case domain.FormatNDJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		return ndjsonDecoder{dec: dec}, nil
+	case domain.FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = 2
+		header, err := reader.Read()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if err == nil && (header[0] != "key" || header[1] != "value") {
+			return nil, errors.New(`csv header must be "key,value"`)
+		}
+		return csvDecoder{r: reader}, nil
+	case domain.FormatMsgpack:
+		return msgpackDecoder{dec: msgpack.NewDecoder(bufio.NewReader(r))}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+type ndjsonEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e ndjsonEncoder) Encode(p domain.Payload) error { return e.enc.Encode(p) }
+func (e ndjsonEncoder) Flush() error                  { return e.w.Flush() }
+
+type ndjsonDecoder struct {
+	dec *json.Decoder
+}
+
+func (d ndjsonDecoder) Decode() (domain.Payload, error) {
+	var r transferRecord
+	if err := d.dec.Decode(&r); err != nil {
+		return domain.Payload{}, err
+	}
+	return r.payload(), nil
+}
+
+// csvEncoder writes one row per key with the value encoded as JSON.
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e csvEncoder) Encode(p domain.Payload) error {
+	value, err := json.Marshal(p.Value)
+	if err != nil {
+		return err
+	}
+	return e.w.Write([]string{p.Key, string(value)})
+}
+
+func (e csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type csvDecoder struct {
+	r *csv.Reader
+}
+
+func (d csvDecoder) Decode() (domain.Payload, error) {
+	row, err := d.r.Read()
+	if err != nil {
+		return domain.Payload{}, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(row[1])))
+	dec.UseNumber()
+
+	r := transferRecord{Key: row[0]}
+	if err := dec.Decode(&r.Value); err != nil {
+		return domain.Payload{}, fmt.Errorf("key %q: %w", row[0], err)
+	}
+	return r.payload(), nil
+}
+
+// msgpackEncoder writes a stream of `[key, value]` arrays, the layout of
+// the Tarantool tuples.
+type msgpackEncoder struct {
+	w   *bufio.Writer
+	enc *msgpack.Encoder
+}
+
+func (e msgpackEncoder) Encode(p domain.Payload) error { return e.enc.Encode(&p) }
+func (e msgpackEncoder) Flush() error                  { return e.w.Flush() }
+
+type msgpackDecoder struct {
+	dec *msgpack.Decoder
+}
+
+func (d msgpackDecoder) Decode() (domain.Payload, error) {
+	var r transferRecord
+	if err := d.dec.Decode(&r); err != nil {
+		return domain.Payload{}, err
+	}
+	return r.payload(), nil
+}
+
+// normalizeNumbers turns JSON numbers into int64 when they are integral and
+// into float64 otherwise, so integers survive an export and import.
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+const (
+	DefaultChildrenLimit = 100
+	MaxChildrenLimit     = 1000
+	// DeleteTreeBatch is the number of keys deleted with pipelined requests.
+	DeleteTreeBatch = 100
+)
+
+var ErrInvalidTree = errors.New("400 invalid tree request")
+
+// TreeUseCase treats keys as paths of segments separated by
+// domain.KeySeparator.
+type TreeUseCase struct {
+	repo interfaces.TreeRepository
+	log  interfaces.Logger
+}
+
+var _ interfaces.TreeUseCase = TreeUseCase{} // TreeUseCase must satisfy interfaces.TreeUseCase
+
+func NewTreeUseCase(repo interfaces.TreeRepository, log interfaces.Logger) TreeUseCase {
+	return TreeUseCase{repo: repo, log: log}
+}
+
+// Children returns one page of the immediate children of path, the root
+// when empty, in key order. The next page starts after the name of the
+// last child.
+func (uc TreeUseCase) Children(ctx context.Context, path, after string, limit int) ([]domain.Child, error) {
+	switch {
+	case limit == 0:
+		limit = DefaultChildrenLimit
+	case limit < 0 || limit > MaxChildrenLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTree, MaxChildrenLimit)
+	}
+
+	return uc.repo.SelectChildren(ctx, domain.ChildPrefix(path), after, limit)
+}
+
+// DeleteTree deletes path and every key below it, and returns the number
+// of keys deleted. Keys are deleted in batches, not atomically: after a
+// failure the keys left can be deleted by calling it again.
+func (uc TreeUseCase) DeleteTree(ctx context.Context, path string) (int, error) {
+	if path == "" {
+		return 0, fmt.Errorf("%w: path is required", ErrInvalidTree)
+	}
+
+	deleted, err := uc.repo.DeleteKeys(ctx, []string{path})
+	if err != nil {
+		return deleted, err
+	}
+
+	prefix, after := domain.ChildPrefix(path), ""
+	for {
+		keys, err := uc.repo.ScanKeys(ctx, prefix, after, DeleteTreeBatch)
+		if err != nil {
+			return deleted, err
+		}
+
+		n, err := uc.repo.DeleteKeys(ctx, keys)
+		deleted += n
+		if err != nil || len(keys) < DeleteTreeBatch {
+			return deleted, err
+		}
+		after = keys[len(keys)-1]
+	}
+}
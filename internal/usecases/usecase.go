@@ -1,31 +1,46 @@
 package usecases
 
 import (
+	"context"
 	"tarantool-app/internal/domain"
 	"tarantool-app/internal/interfaces"
 )
 
 type UserUseCase struct {
-	repo interfaces.Repository
-	log  interfaces.Logger
+	repo      interfaces.Repository
+	validator interfaces.Validator
+	log       interfaces.Logger
 }
 
-func NewUserUseCase(repo interfaces.Repository, log interfaces.Logger) UserUseCase {
-	return UserUseCase{repo: repo, log: log}
+func NewUserUseCase(repo interfaces.Repository, validator interfaces.Validator, log interfaces.Logger) UserUseCase {
+	return UserUseCase{repo: repo, validator: validator, log: log}
 }
 
-func (uc UserUseCase) Create(ap domain.Payload) error {
-	return uc.repo.Insert(ap)
+func (uc UserUseCase) Create(ctx context.Context, ap domain.Payload) error {
+	if err := domain.ValidateKey(ap.Key); err != nil {
+		return err
+	}
+	if err := uc.validator.Validate(ap.Key, ap.Value); err != nil {
+		return err
+	}
+	return uc.repo.Insert(ctx, ap)
 }
 
-func (uc UserUseCase) Update(ap domain.Payload) error {
-	return uc.repo.Update(ap)
+func (uc UserUseCase) Update(ctx context.Context, ap domain.Payload) error {
+	if err := uc.validator.Validate(ap.Key, ap.Value); err != nil {
+		return err
+	}
+	return uc.repo.Update(ctx, ap)
 }
 
-func (uc UserUseCase) Delete(ap domain.Payload) (domain.Payload, error) {
-	return uc.repo.Delete(ap)
+func (uc UserUseCase) Delete(ctx context.Context, ap domain.Payload) (domain.Payload, error) {
+	return uc.repo.Delete(ctx, ap)
 }
 
-func (uc UserUseCase) Read(ap domain.Payload) (domain.Payload, error) {
-	return uc.repo.Select(ap)
+func (uc UserUseCase) Undelete(ctx context.Context, ap domain.Payload) (domain.Payload, error) {
+	return uc.repo.Undelete(ctx, ap)
+}
+
+func (uc UserUseCase) Read(ctx context.Context, ap domain.Payload) (domain.Payload, error) {
+	return uc.repo.Select(ctx, ap)
 }
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"tarantool-app/internal/domain"
+	"tarantool-app/internal/interfaces"
+)
+
+type loggerKey struct{}
+
+type actorKey struct{}
+
+// ContextWithLogger returns a context carrying the request-scoped logger.
+func ContextWithLogger(ctx context.Context, log interfaces.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// LoggerFromContext returns the logger carried by ctx, or fallback when
+// there is none, e.g. outside of a request.
+func LoggerFromContext(ctx context.Context, fallback interfaces.Logger) interfaces.Logger {
+	if log, ok := ctx.Value(loggerKey{}).(interfaces.Logger); ok {
+		return log
+	}
+	return fallback
+}
+
+// ContextWithActor returns a context carrying the client on whose behalf
+// values are changed.
+func ContextWithActor(ctx context.Context, actor domain.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, the system outside of
+// a request.
+func ActorFromContext(ctx context.Context) domain.Actor {
+	if actor, ok := ctx.Value(actorKey{}).(domain.Actor); ok {
+		return actor
+	}
+	return domain.Actor{Principal: domain.PrincipalSystem}
+}
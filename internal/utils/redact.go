@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"tarantool-app/config"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "[REDACTED]"
+
+// keyFields name the log fields holding keys of the store.
+var keyFields = map[string]bool{"key": true, "keys": true}
+
+// Redactor applies the redaction policy of config.RedactionConfig to log
+// fields and to error details returned to clients. A nil Redactor leaves
+// everything as is.
+type Redactor struct {
+	fields   map[string]bool
+	prefixes []string
+	hashKeys bool
+	salt     []byte
+	maxLen   int
+}
+
+// NewRedactor returns the Redactor of cfg, nil when redaction is disabled.
+func NewRedactor(cfg config.RedactionConfig) *Redactor {
+	if cfg.Disabled {
+		return nil
+	}
+
+	fields := make(map[string]bool, len(cfg.Fields))
+	for _, name := range cfg.Fields {
+		fields[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	return &Redactor{
+		fields:   fields,
+		prefixes: cfg.KeyPrefixes,
+		hashKeys: cfg.HashKeys,
+		salt:     []byte(cfg.HashSalt),
+		maxLen:   cfg.MaxValueLength,
+	}
+}
+
+// Fields returns a copy of the key-value pairs of a log call with the
+// policy applied. Arguments that are not preceded by a string name, such
+// as zap fields, are kept.
+func (r *Redactor) Fields(keysAndValues []any) []any {
+	if r == nil || len(keysAndValues) == 0 {
+		return keysAndValues
+	}
+
+	out := make([]any, len(keysAndValues))
+	copy(out, keysAndValues)
+	for i := 0; i < len(out)-1; i++ {
+		name, ok := out[i].(string)
+		if !ok {
+			continue
+		}
+		out[i+1] = r.field(name, out[i+1])
+		i++
+	}
+	return out
+}
+
+func (r *Redactor) field(name string, value any) any {
+	switch {
+	case r.fields[strings.ToLower(name)]:
+		return Redacted
+	case keyFields[name]:
+		switch v := value.(type) {
+		case string:
+			return r.Key(v)
+		case []string:
+			keys := make([]string, len(v))
+			for i, key := range v {
+				keys[i] = r.Key(key)
+			}
+			return keys
+		}
+	}
+	return r.value(value)
+}
+
+// value redacts the fields of nested objects and truncates the result.
+func (r *Redactor) value(value any) any {
+	switch v := value.(type) {
+	case nil, bool, int, int64, uint64, float64, time.Duration, time.Time:
+		return v
+	case string:
+		return r.Message(v)
+	case error:
+		return r.Message(errorMessage(v))
+	case fmt.Stringer:
+		return r.Message(v.String())
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for name, field := range v {
+			if r.fields[strings.ToLower(name)] {
+				object[name] = Redacted
+			} else {
+				object[name] = r.value(field)
+			}
+		}
+		return r.truncated(object)
+	case []any:
+		array := make([]any, len(v))
+		for i, item := range v {
+			array[i] = r.value(item)
+		}
+		return r.truncated(array)
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer:
+		return r.truncated(value)
+	}
+	return value
+}
+
+// truncated returns value itself when its JSON encoding is short enough,
+// the truncated encoding otherwise.
+func (r *Redactor) truncated(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil || len(data) <= r.maxLen {
+		return value
+	}
+	return r.Message(string(data))
+}
+
+// Key returns the key as is, or a salted hash of it when it falls under a
+// sensitive prefix or all keys are hashed. The matched prefix is kept so
+// that hashed keys can still be told apart by their namespace.
+func (r *Redactor) Key(key string) string {
+	if r == nil {
+		return key
+	}
+
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix + r.hash(key)
+		}
+	}
+	if r.hashKeys {
+		return r.hash(key)
+	}
+	return key
+}
+
+func (r *Redactor) hash(key string) string {
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(key))
+	return "#" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// errorMessage returns the text of err, with the message of a wrapped
+// Tarantool error reduced to its code: such messages quote the tuples
+// involved, e.g. the old and new tuples of a duplicate key.
+func errorMessage(err error) string {
+	var tntErr tarantool.Error
+	if !errors.As(err, &tntErr) {
+		return err.Error()
+	}
+	generic := fmt.Sprintf("tarantool error %s (code %d)", tntErr.Code, tntErr.Code)
+	return strings.Replace(err.Error(), tntErr.Error(), generic, 1)
+}
+
+// Message truncates text longer than the configured length, e.g. error
+// details that may quote stored tuples.
+func (r *Redactor) Message(text string) string {
+	if r == nil || len(text) <= r.maxLen {
+		return text
+	}
+
+	cut := r.maxLen
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d bytes)", text[:cut], len(text))
+}
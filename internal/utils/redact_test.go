@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"tarantool-app/config"
+	"testing"
+
+	"github.com/tarantool/go-iproto"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+func TestRedactorFields(t *testing.T) {
+	r := NewRedactor(config.RedactionConfig{
+		Fields:         []string{"value", "password"},
+		KeyPrefixes:    []string{"customers/"},
+		HashSalt:       "salt",
+		MaxValueLength: 64,
+	})
+	duplicate := tarantool.Error{
+		Code: iproto.ER_TUPLE_FOUND,
+		Msg:  `Duplicate key exists in unique index "primary" in space "kv_storage" with old tuple - ["k", {"card": "4111"}]`,
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		value any
+		want  any
+	}{
+		{"redacted field", "value", map[string]any{"card": "4111"}, Redacted},
+		{"field name case", "Password", "hunter2", Redacted},
+		{"nested field", "body", map[string]any{"password": "hunter2", "id": 1}, map[string]any{"password": Redacted, "id": 1}},
+		{"plain key", "key", "orders/1", "orders/1"},
+		{"sensitive key", "key", "customers/42", "customers/" + r.hash("customers/42")},
+		{"short string", "path", "/kv/orders", "/kv/orders"},
+		{"long string", "path", strings.Repeat("a", 80), strings.Repeat("a", 64) + "... (80 bytes)"},
+		{"plain error", "error", errors.New("timeout"), "timeout"},
+		{"tarantool error", "error", duplicate, "tarantool error ER_TUPLE_FOUND (code 3)"},
+		{"wrapped tarantool error", "error", fmt.Errorf("insert: %w", duplicate), "insert: tarantool error ER_TUPLE_FOUND (code 3)"},
+		{"number", "count", 7, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Fields([]any{tt.field, tt.value})
+			if want := []any{tt.field, tt.want}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestNilRedactor(t *testing.T) {
+	var r *Redactor
+	fields := []any{"value", "secret", "key", "customers/42"}
+	if got := r.Fields(fields); !reflect.DeepEqual(got, fields) {
+		t.Fatalf("got %#v, want the fields unchanged", got)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// OpType is the kind of a batch operation.
+type OpType string
+
+const (
+	OpCreate OpType = "create"
+	OpUpdate OpType = "update"
+	OpDelete OpType = "delete"
+)
+
+// batchConcurrency bounds the requests of a batch in flight.
+const batchConcurrency = 8
+
+// Op is an operation of a batch. Value is ignored by deletes.
+type Op struct {
+	Type  OpType
+	Key   string
+	Value map[string]any
+}
+
+// Result is the outcome of the operation at the same index.
+type Result struct {
+	Entry Entry
+	Err   error
+}
+
+// Batch runs the operations concurrently and returns their results in
+// order. The batch is not atomic: some operations may fail while others
+// succeed. The error joins the failures, nil when every operation succeeded.
+func (c *Client) Batch(ctx context.Context, ops []Op) ([]Result, error) {
+	results := make([]Result, len(ops))
+	slots := make(chan struct{}, batchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, op := range ops {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i].Entry, results[i].Err = c.apply(ctx, op)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %q (#%d): %w", ops[i].Type, ops[i].Key, i, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func (c *Client) apply(ctx context.Context, op Op) (Entry, error) {
+	switch op.Type {
+	case OpCreate:
+		return c.Create(ctx, op.Key, op.Value)
+	case OpUpdate:
+		return c.Update(ctx, op.Key, op.Value)
+	case OpDelete:
+		return c.Delete(ctx, op.Key)
+	default:
+		return Entry{}, fmt.Errorf("unknown operation %q", op.Type)
+	}
+}
@@ -0,0 +1,252 @@
+// Package client is a Go client of the tarantool-app REST API.
+//
+//	c, err := client.New("http://localhost:8080", client.WithToken(token))
+//	entry, err := c.Get(ctx, "user:42")
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+//
+// Package clienttest provides a fake server for unit tests of consumers.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Entry is a key with its value.
+type Entry struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	retry   RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithToken sends the token as a bearer token, required by the admin API
+// used by List and Export.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to set a timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// New returns a client of the API served at baseURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+func kvPath(key string) string {
+	return "/kv/" + url.PathEscape(key)
+}
+
+// Get returns the value of the key, ErrNotFound if there is none.
+func (c *Client) Get(ctx context.Context, key string) (Entry, error) {
+	var e Entry
+	return e, c.doJSON(ctx, http.MethodGet, kvPath(key), nil, &e)
+}
+
+// Create stores a new key, ErrConflict if it exists. Creates are not
+// retried, a retry after a lost response would fail with ErrConflict.
+func (c *Client) Create(ctx context.Context, key string, value map[string]any) (Entry, error) {
+	var e Entry
+	return e, c.doJSON(ctx, http.MethodPost, "/kv", Entry{Key: key, Value: value}, &e)
+}
+
+// Update replaces the value of an existing key, ErrNotFound if there is none.
+func (c *Client) Update(ctx context.Context, key string, value map[string]any) (Entry, error) {
+	var e Entry
+	return e, c.doJSON(ctx, http.MethodPut, kvPath(key), map[string]any{"value": value}, &e)
+}
+
+// Delete removes the key and returns its last value, ErrNotFound if there
+// is none. Deletes are not retried, a retry after a lost response would
+// fail with ErrNotFound.
+func (c *Client) Delete(ctx context.Context, key string) (Entry, error) {
+	var e Entry
+	return e, c.doJSON(ctx, http.MethodDelete, kvPath(key), nil, &e)
+}
+
+// List returns every key starting with prefix along with its value,
+// ordered by key. It reads the admin export and needs the admin token.
+func (c *Client) List(ctx context.Context, prefix string) ([]Entry, error) {
+	body, err := c.Export(ctx, prefix, "ndjson")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	entries := []Entry{}
+	dec := json.NewDecoder(bufio.NewReader(body))
+	for {
+		var e Entry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, fmt.Errorf("decode export: %w", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Export streams the values of keys starting with prefix in the format,
+// ndjson, csv or msgpack. The caller closes the returned body. Reading it
+// ends with ErrIncompleteExport instead of io.EOF when the server failed
+// midway.
+func (c *Client) Export(ctx context.Context, prefix, format string) (io.ReadCloser, error) {
+	query := url.Values{"format": {format}}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "/admin/export?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return exportBody{resp}, nil
+}
+
+// exportBody checks the X-Export-Complete trailer once the export is read.
+type exportBody struct {
+	resp *http.Response
+}
+
+func (b exportBody) Read(p []byte) (int, error) {
+	n, err := b.resp.Body.Read(p)
+	if errors.Is(err, io.EOF) && !exportComplete(b.resp) {
+		return n, ErrIncompleteExport
+	}
+	return n, err
+}
+
+func (b exportBody) Close() error {
+	return b.resp.Body.Close()
+}
+
+// exportComplete reads the trailer, sent as a header when the export ended
+// before the body started.
+func exportComplete(resp *http.Response) bool {
+	complete := resp.Trailer.Get("X-Export-Complete")
+	if complete == "" {
+		complete = resp.Header.Get("X-Export-Complete")
+	}
+	return complete == "true"
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// do sends a request, retrying those whose retry answers like the first
+// attempt, and turns non-2xx responses into *Error.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	attempts := c.retry.MaxAttempts
+	if method == http.MethodPost || method == http.MethodDelete {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		if attempt >= attempts || !retryable(resp, err) || ctx.Err() != nil {
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+
+		wait := c.retry.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) //nolint:errcheck
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return c.http.Do(req)
+}
@@ -0,0 +1,154 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"tarantool-app/pkg/client"
+	"tarantool-app/pkg/client/clienttest"
+	"testing"
+)
+
+func TestClientRoundTrip(t *testing.T) {
+	srv := clienttest.NewServer()
+	srv.AdminToken = "secret"
+	defer srv.Close()
+
+	c := srv.Client()
+	ctx := context.Background()
+	ann := map[string]any{"name": "Ann"}
+	bob := map[string]any{"name": "Bob"}
+
+	tests := []struct {
+		name string
+		call func() (client.Entry, error)
+		want client.Entry
+		err  error
+	}{
+		{"get missing", func() (client.Entry, error) { return c.Get(ctx, "user:42") }, client.Entry{}, client.ErrNotFound},
+		{"create", func() (client.Entry, error) { return c.Create(ctx, "user:42", ann) }, client.Entry{Key: "user:42", Value: ann}, nil},
+		{"create existing", func() (client.Entry, error) { return c.Create(ctx, "user:42", bob) }, client.Entry{}, client.ErrConflict},
+		{"create without value", func() (client.Entry, error) { return c.Create(ctx, "user:43", nil) }, client.Entry{}, client.ErrInvalid},
+		{"get", func() (client.Entry, error) { return c.Get(ctx, "user:42") }, client.Entry{Key: "user:42", Value: ann}, nil},
+		{"update", func() (client.Entry, error) { return c.Update(ctx, "user:42", bob) }, client.Entry{Key: "user:42", Value: bob}, nil},
+		{"update missing", func() (client.Entry, error) { return c.Update(ctx, "user:43", bob) }, client.Entry{}, client.ErrNotFound},
+		{"get updated", func() (client.Entry, error) { return c.Get(ctx, "user:42") }, client.Entry{Key: "user:42", Value: bob}, nil},
+		{"key with slash", func() (client.Entry, error) { return c.Create(ctx, "a/b c", ann) }, client.Entry{Key: "a/b c", Value: ann}, nil},
+		{"get key with slash", func() (client.Entry, error) { return c.Get(ctx, "a/b c") }, client.Entry{Key: "a/b c", Value: ann}, nil},
+		{"delete", func() (client.Entry, error) { return c.Delete(ctx, "user:42") }, client.Entry{Key: "user:42", Value: bob}, nil},
+		{"delete missing", func() (client.Entry, error) { return c.Delete(ctx, "user:42") }, client.Entry{}, client.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.call()
+		if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+			t.Fatalf("%s: error %v, want %v", tt.name, err, tt.err)
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	entries, err := c.List(ctx, "")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if want := []client.Entry{{Key: "a/b c", Value: ann}}; !reflect.DeepEqual(entries, want) {
+		t.Fatalf("list: got %+v, want %+v", entries, want)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []int
+		call     func(*client.Client) error
+		requests int
+		err      error
+	}{
+		{"get retried", []int{http.StatusServiceUnavailable, http.StatusBadGateway}, getKey, 3, nil},
+		{"get gives up", []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}, getKey, 3, client.ErrServer},
+		{"get not retried on 500", []int{http.StatusInternalServerError}, getKey, 1, client.ErrServer},
+		{"update retried", []int{http.StatusTooManyRequests}, updateKey, 2, nil},
+		{"create not retried", []int{http.StatusServiceUnavailable}, createKey, 1, client.ErrServer},
+		{"delete not retried", []int{http.StatusServiceUnavailable}, deleteKey, 1, client.ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := clienttest.NewServer()
+			defer srv.Close()
+			srv.Set("user:42", map[string]any{"name": "Ann"})
+			srv.FailNext(tt.failures...)
+
+			err := tt.call(srv.Client())
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("error %v, want %v", err, tt.err)
+			}
+			if got := srv.Requests(); got != tt.requests {
+				t.Fatalf("%d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestClientExport(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		token      string
+		err        error
+	}{
+		{"admin token", "secret", "secret", nil},
+		{"wrong token", "secret", "nope", client.ErrUnauthorized},
+		{"admin API disabled", "", "", client.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := clienttest.NewServer()
+			defer srv.Close()
+			srv.AdminToken = tt.adminToken
+			srv.Set("user:42", map[string]any{"name": "Ann"})
+
+			body, err := srv.Client(client.WithToken(tt.token)).Export(context.Background(), "user:", "ndjson")
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			defer body.Close()
+
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("read export: %v", err)
+			}
+			if want := "{\"key\":\"user:42\",\"value\":{\"name\":\"Ann\"}}\n"; string(data) != want {
+				t.Fatalf("export %q, want %q", data, want)
+			}
+		})
+	}
+}
+
+func getKey(c *client.Client) error {
+	_, err := c.Get(context.Background(), "user:42")
+	return err
+}
+
+func createKey(c *client.Client) error {
+	_, err := c.Create(context.Background(), "user:43", map[string]any{"name": "Bob"})
+	return err
+}
+
+func updateKey(c *client.Client) error {
+	_, err := c.Update(context.Background(), "user:42", map[string]any{"name": "Bob"})
+	return err
+}
+
+func deleteKey(c *client.Client) error {
+	_, err := c.Delete(context.Background(), "user:42")
+	return err
+}
@@ -0,0 +1,246 @@
+// Package clienttest provides an in-memory fake of the tarantool-app API
+// for unit tests of client consumers:
+//
+//	srv := clienttest.NewServer()
+//	defer srv.Close()
+//	srv.Set("user:42", map[string]any{"name": "Ann"})
+//	c := srv.Client()
+package clienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"tarantool-app/pkg/client"
+	"time"
+)
+
+// Server serves /kv and the NDJSON /admin/export from memory, with the
+// status codes and error bodies of the real API.
+type Server struct {
+	*httptest.Server
+
+	// AdminToken guards /admin like the real server; the admin API is
+	// disabled while it is empty.
+	AdminToken string
+
+	mu       sync.Mutex
+	values   map[string]map[string]any
+	failures []int
+	requests int
+}
+
+// NewServer starts a fake server. Close it when done.
+func NewServer() *Server {
+	s := &Server{values: map[string]map[string]any{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /kv/{id}", s.get)
+	mux.HandleFunc("POST /kv", s.create)
+	mux.HandleFunc("PUT /kv/{id}", s.update)
+	mux.HandleFunc("DELETE /kv/{id}", s.delete)
+	mux.HandleFunc("GET /admin/export", s.export)
+
+	s.Server = httptest.NewServer(s.count(mux))
+	return s
+}
+
+// Client returns a client of the server. Retries sleep for at most a
+// millisecond unless replaced by options.
+func (s *Server) Client(opts ...client.Option) *client.Client {
+	opts = append([]client.Option{
+		client.WithToken(s.AdminToken),
+		client.WithHTTPClient(s.Server.Client()),
+		client.WithRetry(client.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Microsecond, MaxBackoff: time.Millisecond}),
+	}, opts...)
+
+	c, err := client.New(s.URL, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Set stores the value of the key.
+func (s *Server) Set(key string, value map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Value returns the stored value of the key.
+func (s *Server) Value(key string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Keys returns the stored keys in order.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// FailNext answers the next requests with the status codes, one per
+// request, before serving normally again.
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Requests returns the number of requests served, failed ones included.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		var status int
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			writeJSON(w, status, map[string]any{"error": fmt.Sprintf("%d %s", status, http.StatusText(status))})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("id")
+	value, ok := s.Value(key)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "404 key not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"key": key, "value": value})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var body client.Entry
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid request body format"})
+		return
+	}
+	switch {
+	case body.Key == "":
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing key"})
+		return
+	case len(body.Value) == 0:
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing value"})
+		return
+	}
+
+	s.mu.Lock()
+	_, exists := s.values[body.Key]
+	if !exists {
+		s.values[body.Key] = body.Value
+	}
+	s.mu.Unlock()
+
+	if exists {
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "409 key already exists"})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"message": "created", "key": body.Key, "value": body.Value})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Value map[string]any `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid request body format"})
+		return
+	}
+	if len(body.Value) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing value"})
+		return
+	}
+
+	key := r.PathValue("id")
+	s.mu.Lock()
+	_, exists := s.values[key]
+	if exists {
+		s.values[key] = body.Value
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "404 key not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"message": "updated", "key": key, "value": body.Value})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("id")
+	s.mu.Lock()
+	value, exists := s.values[key]
+	delete(s.values, key)
+	s.mu.Unlock()
+
+	if !exists {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "404 key not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"message": "deleted", "key": key, "value": value})
+}
+
+func (s *Server) export(w http.ResponseWriter, r *http.Request) {
+	switch token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); {
+	case s.AdminToken == "":
+		writeJSON(w, http.StatusForbidden, map[string]any{"error": "403 admin API is disabled"})
+		return
+	case !ok || token != s.AdminToken:
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "401 unauthorized"})
+		return
+	}
+	if format := r.URL.Query().Get("format"); format != "" && format != "ndjson" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "the fake server only exports ndjson"})
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Trailer", "X-Export-Count, X-Export-Complete")
+	enc := json.NewEncoder(w)
+	exported := 0
+	for _, key := range s.Keys() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if value, ok := s.Value(key); ok {
+			enc.Encode(client.Entry{Key: key, Value: value}) //nolint:errcheck
+			exported++
+		}
+	}
+	w.Header().Set("X-Export-Count", strconv.Itoa(exported))
+	w.Header().Set("X-Export-Complete", "true")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body) //nolint:errcheck
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors matched by errors.Is against an *Error of the API.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrInvalid      = errors.New("invalid request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrServer       = errors.New("server error")
+)
+
+// ErrIncompleteExport ends an export the server could not complete.
+var ErrIncompleteExport = errors.New("export incomplete")
+
+// Error is a non-2xx response of the API. Message is the "error" field of
+// the body, Details the schema violations of a rejected value.
+type Error struct {
+	StatusCode int
+	Message    string
+	Details    any
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+	if e.Details != nil {
+		details, _ := json.Marshal(e.Details)
+		msg += ": " + string(details)
+	}
+	return msg
+}
+
+// Is maps the status code to one of the exported errors.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrInvalid:
+		return e.StatusCode == http.StatusBadRequest ||
+			e.StatusCode == http.StatusUnsupportedMediaType ||
+			e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// decodeError reads the error body of a response.
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Error   string `json:"error"`
+		Details any    `json:"details"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+		apiErr.Message = body.Error
+		apiErr.Details = body.Details
+	}
+	return apiErr
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries idempotent requests failing with a transport error,
+// 429 or 502 to 504, sleeping an exponentially growing, jittered backoff.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first one, 1 disables retries
+	MinBackoff  time.Duration // before the second attempt
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy is used by clients created without WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+// retryable reports whether the response, or its absence, is worth another
+// attempt of an idempotent request.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the wait before the attempt following the given one,
+// counted from 1, honouring a Retry-After in seconds.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, p.MaxBackoff)
+		}
+	}
+
+	wait := p.MinBackoff << (attempt - 1)
+	if wait <= 0 || wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	// Equal jitter keeps clients from retrying in step.
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
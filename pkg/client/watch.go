@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// DefaultWatchInterval is used by Watch for a non-positive interval.
+const DefaultWatchInterval = time.Second
+
+// EventType tells what happened to a watched key.
+type EventType int
+
+const (
+	EventPut    EventType = iota + 1 // created or changed, including the first value seen
+	EventDelete                      // deleted after having been seen
+	EventError                       // polling failed, Watch keeps polling
+)
+
+type Event struct {
+	Type  EventType
+	Entry Entry
+	Err   error
+}
+
+// Watch polls the key every interval, the API having no change
+// notifications, and sends an event whenever the value changes. The
+// channel is closed once ctx is done.
+func (c *Client) Watch(ctx context.Context, key string, interval time.Duration) <-chan Event {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *Entry
+		for {
+			var event *Event
+			entry, err := c.Get(ctx, key)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case errors.Is(err, ErrNotFound):
+				if last != nil {
+					event = &Event{Type: EventDelete, Entry: *last}
+					last = nil
+				}
+			case err != nil:
+				event = &Event{Type: EventError, Entry: Entry{Key: key}, Err: err}
+			case last == nil || !reflect.DeepEqual(last.Value, entry.Value):
+				last = &entry
+				event = &Event{Type: EventPut, Entry: entry}
+			}
+
+			if event != nil {
+				select {
+				case events <- *event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}